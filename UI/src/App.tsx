@@ -84,7 +84,7 @@ export const App: React.FC = () => {
       <Route
         path="/users"
         element={
-          <ProtectedRoute allowedRoles={["Admin"]}>
+          <ProtectedRoute permission="user:read">
             <Layout>
               <UsersPage />
             </Layout>
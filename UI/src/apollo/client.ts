@@ -2,13 +2,75 @@ import { InMemoryCache } from "@apollo/client";
 import { ApolloClient, HttpLink } from "@apollo/client";
 import { SetContextLink } from "@apollo/client/link/context";
 
+const API_URL = "http://localhost:8080/query";
+
+export const AUTH_TOKEN_KEY = "auth_token";
+export const REFRESH_TOKEN_KEY = "auth_refresh_token";
+export const TOKEN_EXPIRES_KEY = "auth_token_expires_at";
+
+// Refresh a little before the access token actually expires to absorb clock skew.
+const REFRESH_MARGIN_MS = 30_000;
+
+const REFRESH_MUTATION = `
+  mutation RefreshToken($refreshToken: String!) {
+    refreshToken(refreshToken: $refreshToken) { token refreshToken expiresAt }
+  }
+`;
+
+export const storeTokens = (token: string, refreshToken: string, expiresAt: string) => {
+    localStorage.setItem(AUTH_TOKEN_KEY, token);
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+    localStorage.setItem(TOKEN_EXPIRES_KEY, expiresAt);
+};
+
+export const clearTokens = () => {
+    localStorage.removeItem(AUTH_TOKEN_KEY);
+    localStorage.removeItem(REFRESH_TOKEN_KEY);
+    localStorage.removeItem(TOKEN_EXPIRES_KEY);
+};
+
+// Refresh tokens are single-use, so concurrent requests must share one refresh call.
+let refreshing: Promise<string | null> | null = null;
+
+const refreshAccessToken = async (): Promise<string | null> => {
+    const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+    if (!refreshToken) return null;
+    try {
+        const res = await fetch(API_URL, {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ query: REFRESH_MUTATION, variables: { refreshToken } }),
+        });
+        const body = await res.json();
+        const payload = body?.data?.refreshToken;
+        if (!payload) {
+            clearTokens();
+            return null;
+        }
+        storeTokens(payload.token, payload.refreshToken, payload.expiresAt);
+        return payload.token;
+    } catch {
+        return localStorage.getItem(AUTH_TOKEN_KEY);
+    }
+};
+
+const currentToken = async (): Promise<string | null> => {
+    const token = localStorage.getItem(AUTH_TOKEN_KEY);
+    const expiresAt = localStorage.getItem(TOKEN_EXPIRES_KEY);
+    if (!token || !expiresAt || Date.parse(expiresAt) - Date.now() > REFRESH_MARGIN_MS) {
+        return token;
+    }
+    refreshing ??= refreshAccessToken().finally(() => { refreshing = null; });
+    return refreshing;
+};
+
 const httpLink = new HttpLink({
-    uri: "http://localhost:8080/query",
+    uri: API_URL,
 });
 
-// Auth link to inject Authorization header on each request
-const authLink = new SetContextLink(({ headers }) => {
-    const token = localStorage.getItem("auth_token");
+// Auth link to inject Authorization header on each request, refreshing the access token when it is about to expire
+const authLink = new SetContextLink(async ({ headers }) => {
+    const token = await currentToken();
     return {
         headers: {
             ...headers,
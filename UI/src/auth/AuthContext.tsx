@@ -1,11 +1,14 @@
 import React, { createContext, useEffect, useState } from "react";
 import { gql } from "@apollo/client";
-import { useLazyQuery } from "@apollo/client/react";
+import { useLazyQuery, useMutation } from "@apollo/client/react";
+import { AUTH_TOKEN_KEY, clearTokens } from "../apollo/client";
 
-export type RoleName = "Admin" | "Editor" | "Viewer";
+// Built-in roles; Admins may add custom ones, so checks should use permissions instead.
+export type RoleName = "Admin" | "Editor" | "Viewer" | (string & {});
 
 export interface Role {
     name: RoleName;
+    permissions: string[];
 }
 export interface AuthUser {
     id: string;
@@ -30,7 +33,12 @@ export const AuthContext = createContext<AuthContextValue>({
 });
 
 const AUTH_STORAGE_KEY = "auth";
-const AUTH_TOKEN_KEY = "auth_token";
+
+const LOGOUT_MUTATION = gql`
+  mutation Logout {
+    logout
+  }
+`;
 
 const ME_QUERY = gql`
   query Me {
@@ -39,11 +47,16 @@ const ME_QUERY = gql`
       email
       role {
         name
+        permissions
       }
     }
   }
 `;
 
+// can reports whether the user's role holds perm, e.g. "vehicle:update".
+export const can = (user: AuthUser | null, perm: string): boolean =>
+    !!user?.role.permissions?.includes(perm);
+
 export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children }) => {
     const [user, setUser] = useState<AuthUser | null>(null);
     const [token, setToken] = useState<string | null>(null);
@@ -51,6 +64,7 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
     const [fetchMe, { data: meData }] = useLazyQuery<{ me: AuthUser }>(ME_QUERY, {
         fetchPolicy: "network-only",
     });
+    const [logoutMutation] = useMutation(LOGOUT_MUTATION);
 
     useEffect(() => {
         let active = true;
@@ -104,10 +118,11 @@ export const AuthProvider: React.FC<{ children: React.ReactNode }> = ({ children
     };
 
     const logout = () => {
+        // Revoke the session server-side; local state is cleared regardless of the outcome.
+        logoutMutation().catch(() => { }).finally(clearTokens);
         setToken(null);
         setUser(null);
         localStorage.removeItem(AUTH_STORAGE_KEY);
-        localStorage.removeItem(AUTH_TOKEN_KEY);
     };
 
     return (
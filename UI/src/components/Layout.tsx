@@ -2,6 +2,7 @@ import React from "react";
 import { AppBar, Avatar, Box, Button, IconButton, Toolbar, Tooltip, Typography, Stack, Divider, Chip } from "@mui/material";
 import { Brightness4, Brightness7 } from "@mui/icons-material";
 import { useAuth } from "../auth/useAuth";
+import { can } from "../auth/AuthContext";
 import { useNavigate } from "react-router-dom";
 import { useTheme } from "@mui/material/styles";
 
@@ -14,7 +15,7 @@ export const Layout: React.FC<{ children: React.ReactNode }> = ({ children }) =>
         logout();
         navigate("/login");
     };
-
+
     return (
         <Box sx={{ minHeight: "100vh", bgcolor: "background.default" }}>
             <AppBar position="static" color="default" elevation={1}>
@@ -37,7 +38,7 @@ export const Layout: React.FC<{ children: React.ReactNode }> = ({ children }) =>
 
                             <Divider flexItem orientation="vertical" sx={{ mx: 1 }} />
 
-                            {user.role.name === "Admin" && (
+                            {can(user, "user:read") && (
                                 <Button color="primary" variant="text" onClick={() => navigate("/users")}>
                                     Users
                                 </Button>
@@ -25,11 +25,30 @@ export interface MovementLogItem {
     description?: string | null;
     occurredAt: string;
     createdAt: string;
-    createdBy: string;
+    voidedAt?: string | null;
+    voidReason?: string | null;
+    reversesId?: string | null;
+    reversedById?: string | null;
+    fromLocation?: { name: string } | null;
+    toLocation?: { name: string } | null;
+    createdBy?: { id: string; email: string } | null;
+}
+
+export interface LocationOption {
+    id: string;
+    name: string;
+    kind: string;
+}
+
+export interface MovementTypeOption {
+    name: string;
+    locationRule: "NONE" | "OPTIONAL" | "REQUIRED";
 }
 
 interface MovementLogSectionProps {
     movements: MovementLogItem[];
+    locations: LocationOption[];
+    movementTypes: MovementTypeOption[];
     canCreate: boolean;
     loading?: boolean;
     onCreate: (input: MovementFormValues) => Promise<void>;
@@ -39,18 +58,20 @@ export type MovementFormValues = {
     type: string;
     description: string;
     occurredAt: string;
+    toLocationId: string;
 };
 
-const movementTypes = ["SALE", "DEFECT", "DISCONTINUED", "TRANSFER", "RETURN"];
-
 const defaultMovementForm: MovementFormValues = {
     type: "SALE",
     description: "",
     occurredAt: new Date().toISOString().slice(0, 16),
+    toLocationId: "",
 };
 
 export const MovementLogSection: React.FC<MovementLogSectionProps> = ({
     movements,
+    locations,
+    movementTypes,
     canCreate,
     loading,
     onCreate,
@@ -60,6 +81,7 @@ export const MovementLogSection: React.FC<MovementLogSectionProps> = ({
     const [formError, setFormError] = useState<string | null>(null);
 
     const rows = useMemo(() => movements ?? [], [movements]);
+    const locationRule = movementTypes.find((t) => t.name === form.type)?.locationRule ?? "NONE";
 
     const handleOpen = () => {
         setForm({ ...defaultMovementForm, occurredAt: new Date().toISOString().slice(0, 16) });
@@ -102,6 +124,7 @@ export const MovementLogSection: React.FC<MovementLogSectionProps> = ({
                     <TableRow>
                         <TableCell>Type</TableCell>
                         <TableCell>Description</TableCell>
+                        <TableCell>Location</TableCell>
                         <TableCell>Occurred At</TableCell>
                         <TableCell>Created At</TableCell>
                         <TableCell>Created By</TableCell>
@@ -109,17 +132,27 @@ export const MovementLogSection: React.FC<MovementLogSectionProps> = ({
                 </TableHead>
                 <TableBody>
                     {rows.map((movement) => (
-                        <TableRow key={movement.id}>
-                            <TableCell>{movement.type}</TableCell>
+                        <TableRow key={movement.id} sx={movement.voidedAt ? { opacity: 0.5 } : undefined}>
+                            <TableCell>
+                                {movement.type}
+                                {movement.voidedAt && ` (void: ${movement.voidReason})`}
+                                {movement.reversesId && " (reversal)"}
+                                {movement.reversedById && " (reversed)"}
+                            </TableCell>
                             <TableCell>{movement.description ?? "-"}</TableCell>
+                            <TableCell>
+                                {movement.toLocation || movement.fromLocation
+                                    ? `${movement.fromLocation?.name ?? "-"} → ${movement.toLocation?.name ?? "-"}`
+                                    : "-"}
+                            </TableCell>
                             <TableCell>{new Date(movement.occurredAt).toLocaleString()}</TableCell>
                             <TableCell>{new Date(movement.createdAt).toLocaleString()}</TableCell>
-                            <TableCell>{movement.createdBy}</TableCell>
+                            <TableCell>{movement.createdBy?.email ?? "-"}</TableCell>
                         </TableRow>
                     ))}
                     {rows.length === 0 && (
                         <TableRow>
-                            <TableCell colSpan={5} align="center">
+                            <TableCell colSpan={6} align="center">
                                 No movements recorded.
                             </TableCell>
                         </TableRow>
@@ -138,15 +171,34 @@ export const MovementLogSection: React.FC<MovementLogSectionProps> = ({
                                 label="Type"
                                 name="type"
                                 value={form.type}
-                                onChange={(e) => setForm((prev) => ({ ...prev, type: e.target.value }))}
+                                onChange={(e) => setForm((prev) => ({ ...prev, type: e.target.value, toLocationId: "" }))}
                             >
                                 {movementTypes.map((type) => (
-                                    <MenuItem key={type} value={type}>
-                                        {type}
+                                    <MenuItem key={type.name} value={type.name}>
+                                        {type.name}
                                     </MenuItem>
                                 ))}
                             </Select>
                         </FormControl>
+                        {locationRule !== "NONE" && (
+                            <FormControl fullWidth required={locationRule === "REQUIRED"}>
+                                <InputLabel id="movement-location-label">Destination</InputLabel>
+                                <Select
+                                    labelId="movement-location-label"
+                                    label="Destination"
+                                    name="toLocationId"
+                                    value={form.toLocationId}
+                                    onChange={(e) => setForm((prev) => ({ ...prev, toLocationId: e.target.value }))}
+                                >
+                                    {locationRule === "OPTIONAL" && <MenuItem value="">None</MenuItem>}
+                                    {locations.map((l) => (
+                                        <MenuItem key={l.id} value={l.id}>
+                                            {l.name} ({l.kind})
+                                        </MenuItem>
+                                    ))}
+                                </Select>
+                            </FormControl>
+                        )}
                         <TextField
                             label="Occurred At"
                             name="occurredAt"
@@ -1,13 +1,15 @@
 import React from "react";
 import { Navigate } from "react-router-dom";
 import { useAuth } from "../auth/useAuth";
+import { can } from "../auth/AuthContext";
 
 interface Props {
     children: JSX.Element;
     allowedRoles?: string[];
+    permission?: string;
 }
 
-export const ProtectedRoute: React.FC<Props> = ({ children, allowedRoles }) => {
+export const ProtectedRoute: React.FC<Props> = ({ children, allowedRoles, permission }) => {
     const { user, loading } = useAuth();
 
     if (loading) {
@@ -18,7 +20,7 @@ export const ProtectedRoute: React.FC<Props> = ({ children, allowedRoles }) => {
         return <Navigate to="/login" replace />;
     }
 
-    if (allowedRoles && !allowedRoles.includes(user.role.name)) {
+    if ((allowedRoles && !allowedRoles.includes(user.role.name)) || (permission && !can(user, permission))) {
         return <div>Access denied.</div>;
     }
 
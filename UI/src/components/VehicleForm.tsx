@@ -23,7 +23,7 @@ interface VehicleFormProps {
 }
 
 const tractionOptions = ["RWD", "FWD", "AWD", "FOUR_WD"];
-const statusOptions = ["ACTIVE", "INACTIVE", "DISCONTINUED"];
+const statusOptions = ["ACTIVE", "INACTIVE", "SOLD", "DISCONTINUED"];
 
 export const VehicleForm: React.FC<VehicleFormProps> = ({
     title,
@@ -75,7 +75,8 @@ export const VehicleForm: React.FC<VehicleFormProps> = ({
                 type="number"
                 value={values.releaseYear}
                 onChange={handleChange}
-                required
+                required={isEdit}
+                helperText={isEdit ? undefined : "Leave empty to decode it from the VIN"}
             />
             <TextField label="Batch Number" name="batchNumber" value={values.batchNumber} onChange={handleChange} required />
             <TextField label="Color" name="color" value={values.color} onChange={handleChange} />
@@ -87,7 +88,15 @@ export const VehicleForm: React.FC<VehicleFormProps> = ({
                 onChange={handleChange}
                 required
             />
-            <TextField select label="Status" name="status" value={values.status} onChange={handleChange}>
+            <TextField
+                select
+                label="Status"
+                name="status"
+                value={values.status}
+                onChange={handleChange}
+                disabled
+                helperText={isEdit ? "Changes through movements" : "New vehicles start ACTIVE"}
+            >
                 {statusOptions.map((option) => (
                     <MenuItem key={option} value={option}>
                         {option}
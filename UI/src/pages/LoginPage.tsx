@@ -5,17 +5,21 @@ import { useAuth } from "../auth/useAuth";
 import { useNavigate } from "react-router-dom";
 import { useMutation } from "@apollo/client/react";
 import { AuthUser } from "../auth/AuthContext";
+import { storeTokens } from "../apollo/client";
 
 //  -- GraphQL Mutation --
 const LOGIN_MUTATION = gql`
   mutation Login($email: String!, $password: String!) {
     login(email: $email, password: $password) {
       token
+      refreshToken
+      expiresAt
       user {
         id
         email
         role {
           name
+          permissions
         }
       }
     }
@@ -28,7 +32,7 @@ export const LoginPage: React.FC = () => {
     const { login, loading: authLoading } = useAuth();
     const navigate = useNavigate();
     const [form, setForm] = useState({ email: "main", password: "" });
-    const [loginMutation, { loading, error }] = useMutation<{ login: { token: string, user: AuthUser } }>(LOGIN_MUTATION);
+    const [loginMutation, { loading, error }] = useMutation<{ login: { token: string, refreshToken: string, expiresAt: string, user: AuthUser } }>(LOGIN_MUTATION);
     const [formError, setFormError] = useState<string | null>(null);
 
     // -- Handlers --
@@ -46,7 +50,8 @@ export const LoginPage: React.FC = () => {
                 return;
             }
 
-            const { token, user } = res.data.login;
+            const { token, refreshToken, expiresAt, user } = res.data.login;
+            storeTokens(token, refreshToken, expiresAt);
             login(token, {
                 id: user.id,
                 email: user.email,
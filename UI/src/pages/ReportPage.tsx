@@ -24,6 +24,36 @@ const MOVEMENT_REPORT = gql`
   }
 `;
 
+const FLEET_STATS = gql`
+  query FleetStats {
+    fleetStats {
+      totalVehicles
+      byStatus { status count }
+      byTractionType { tractionType count }
+      soldVehicles
+      returnRateAfterSale
+      meanDaysToSale
+      defectRateByBatch { batchNumber vehicles defects defectRate }
+      computedAt
+    }
+  }
+`;
+
+interface FleetStatsData {
+    fleetStats: {
+        totalVehicles: number;
+        byStatus: { status: string; count: number }[];
+        byTractionType: { tractionType: string; count: number }[];
+        soldVehicles: number;
+        returnRateAfterSale: number | null;
+        meanDaysToSale: number | null;
+        defectRateByBatch: { batchNumber: string; vehicles: number; defects: number; defectRate: number }[];
+        computedAt: string;
+    };
+}
+
+const pct = (v: number | null) => (v === null ? "—" : `${(v * 100).toFixed(1)}%`);
+
 interface MovementReportRow {
     type: string;
     count: number;
@@ -55,9 +85,42 @@ export const ReportPage: React.FC = () => {
     };
 
     const rows = data?.movementReport ?? [];
+    const { data: statsData, error: statsError } = useQuery<FleetStatsData>(FLEET_STATS);
+    const stats = statsData?.fleetStats;
 
     return (
         <Box sx={{ display: "flex", flexDirection: "column", gap: 3 }}>
+            <Typography variant="h4">Fleet Overview</Typography>
+
+            <Paper sx={{ p: 2 }}>
+                {statsError && <Alert severity="error">{statsError.message}</Alert>}
+                {stats && (
+                    <Box sx={{ display: "flex", flexDirection: "column", gap: 1 }}>
+                        <Typography>
+                            {stats.totalVehicles} vehicles ·{" "}
+                            {stats.byStatus.map((s) => `${s.status} ${s.count}`).join(", ")}
+                        </Typography>
+                        <Typography>
+                            Traction: {stats.byTractionType.map((t) => `${t.tractionType} ${t.count}`).join(", ") || "—"}
+                        </Typography>
+                        <Typography>
+                            Sold {stats.soldVehicles} · returned after sale {pct(stats.returnRateAfterSale)} · mean time to sale{" "}
+                            {stats.meanDaysToSale === null ? "—" : `${stats.meanDaysToSale.toFixed(1)} days`}
+                        </Typography>
+                        <Typography>
+                            Highest defect rate:{" "}
+                            {stats.defectRateByBatch
+                                .slice(0, 5)
+                                .map((b) => `${b.batchNumber} ${pct(b.defectRate)} (${b.defects}/${b.vehicles})`)
+                                .join(", ") || "—"}
+                        </Typography>
+                        <Typography variant="caption" color="text.secondary">
+                            As of {new Date(stats.computedAt).toLocaleString()}
+                        </Typography>
+                    </Box>
+                )}
+            </Paper>
+
             <Typography variant="h4">Movement Report</Typography>
 
             <Paper sx={{ p: 2, display: "flex", gap: 2, flexWrap: "wrap", alignItems: "flex-end" }}>
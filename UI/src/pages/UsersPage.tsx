@@ -15,14 +15,19 @@ import {
 } from "@mui/material";
 import { useQuery, useMutation } from "@apollo/client/react";
 import { useAuth } from "../auth/useAuth";
+import { can } from "../auth/AuthContext";
 
 const USERS_QUERY = gql`
-  query Users($limit: Int, $offset: Int) {
-    users(limit: $limit, offset: $offset) {
-      id
-      email
-      role {
-        name
+  query Users($first: Int, $after: String) {
+    users(first: $first, after: $after) {
+      edges {
+        node {
+          id
+          email
+          role {
+            name
+          }
+        }
       }
     }
   }
@@ -46,21 +51,30 @@ const SIGNUP_MUTATION = gql`
   }
 `;
 
-const roleOptions = ["Admin", "Editor", "Viewer"];
+const ROLES_QUERY = gql`
+  query Roles {
+    roles {
+      id
+      name
+    }
+  }
+`;
 
 export const UsersPage: React.FC = () => {
     const { user } = useAuth();
-    const { data, loading, error, refetch } = useQuery<{ users: { id: string; email: string; role?: { name: string } }[] }>(USERS_QUERY, { variables: { limit: 50, offset: 0 } });
+    const { data, loading, error, refetch } = useQuery<{ users: { edges: { node: { id: string; email: string; role?: { name: string } } }[] } }>(USERS_QUERY, { variables: { first: 50 } });
+    const { data: rolesData } = useQuery<{ roles: { id: string; name: string }[] }>(ROLES_QUERY);
+    const roleOptions = rolesData?.roles.map((r) => r.name) ?? [];
     const [changeRole] = useMutation(CHANGE_ROLE_MUTATION);
     const [signup, signupState] = useMutation(SIGNUP_MUTATION);
     const [newUser, setNewUser] = useState({ email: "", password: "" });
     const [inlineError, setInlineError] = useState<string | null>(null);
 
-    if (!user || user.role.name !== "Admin") {
+    if (!can(user, "user:read")) {
         return <Typography>You do not have permission to view this page.</Typography>;
     }
 
-    const users = data?.users ?? [];
+    const users = data?.users.edges.map((e) => e.node) ?? [];
 
     const handleRoleChange = async (userId: string, newRole: string) => {
         setInlineError(null);
@@ -5,6 +5,7 @@ import { Box, CircularProgress, Typography } from "@mui/material";
 import { useNavigate } from "react-router-dom";
 import { VehicleForm, VehicleFormValues } from "../components/VehicleForm";
 import { useAuth } from "../auth/useAuth";
+import { can } from "../auth/AuthContext";
 
 interface CreateVehiclePayload {
     createVehicle: {
@@ -27,7 +28,7 @@ const defaultValues: VehicleFormValues = {
     name: "",
     modelCode: "",
     tractionType: "RWD",
-    releaseYear: new Date().getFullYear().toString(),
+    releaseYear: "", // decoded from the VIN when left empty
     batchNumber: "",
     color: "",
     mileage: "0",
@@ -39,7 +40,7 @@ export const VehicleCreatePage: React.FC = () => {
     const navigate = useNavigate();
     const [createVehicle, { loading, error }] = useMutation<CreateVehiclePayload>(CREATE_VEHICLE_MUTATION);
 
-    if (!user || user.role.name === "Viewer") {
+    if (!can(user, "vehicle:create")) {
         return (
             <Box>
                 <Typography variant="h6">You do not have permission to create vehicles.</Typography>
@@ -55,11 +56,10 @@ export const VehicleCreatePage: React.FC = () => {
                     name: values.name,
                     modelCode: values.modelCode,
                     tractionType: values.tractionType,
-                    releaseYear: Number(values.releaseYear),
+                    releaseYear: values.releaseYear ? Number(values.releaseYear) : null,
                     batchNumber: values.batchNumber,
                     color: values.color || null,
                     mileage: Number(values.mileage),
-                    status: values.status,
                 },
             },
         });
@@ -10,7 +10,6 @@ import {
     DialogContent,
     DialogContentText,
     DialogTitle,
-    MenuItem,
     Paper,
     TextField,
     Typography,
@@ -18,10 +17,17 @@ import {
 import { useMutation, useQuery } from "@apollo/client/react";
 import { useNavigate, useParams } from "react-router-dom";
 import { useAuth } from "../auth/useAuth";
-import { MovementLogSection, MovementFormValues, MovementLogItem } from "../components/MovementLogSection";
+import { can } from "../auth/AuthContext";
+import {
+    MovementLogSection,
+    MovementFormValues,
+    MovementLogItem,
+    LocationOption,
+    MovementTypeOption,
+} from "../components/MovementLogSection";
 
 const VEHICLE_QUERY = gql`
-  query Vehicle($id: ID!, $movementsLimit: Int, $movementsOffset: Int) {
+  query Vehicle($id: ID!, $movementsFirst: Int, $movementsAfter: String, $withCreatedBy: Boolean!) {
     vehicle(id: $id) {
       id
       vin
@@ -29,24 +35,68 @@ const VEHICLE_QUERY = gql`
       modelCode
       tractionType
       releaseYear
+      releaseYearMismatch
+      manufacturer
+      currentLocation {
+        id
+        name
+        kind
+      }
       batchNumber
       color
       mileage
       status
       createdAt
       updatedAt
-      movements(limit: $movementsLimit, offset: $movementsOffset) {
-        id
-        type
-        description
-        occurredAt
-        createdAt
-        createdBy
+      movements(first: $movementsFirst, after: $movementsAfter) {
+        edges {
+          node {
+            id
+            type
+            description
+            occurredAt
+            createdAt
+            voidedAt
+            voidReason
+            reversesId
+            reversedById
+            fromLocation {
+              name
+            }
+            toLocation {
+              name
+            }
+            createdBy @include(if: $withCreatedBy) {
+              id
+              email
+            }
+          }
+        }
+        totalCount
       }
     }
   }
 `;
 
+const LOCATIONS_QUERY = gql`
+  query Locations {
+    locations {
+      id
+      name
+      kind
+    }
+  }
+`;
+
+const MOVEMENT_TYPES_QUERY = gql`
+  query MovementTypes {
+    movementTypes {
+      name
+      locationRule
+    }
+  }
+`;
+
 const UPDATE_VEHICLE_MUTATION = gql`
   mutation UpdateVehicle($id: ID!, $input: VehicleUpdateInput!) {
     updateVehicle(id: $id, input: $input) {
@@ -56,6 +106,8 @@ const UPDATE_VEHICLE_MUTATION = gql`
       modelCode
       tractionType
       releaseYear
+      releaseYearMismatch
+      manufacturer
       batchNumber
       color
       mileage
@@ -80,7 +132,12 @@ const CREATE_MOVEMENT_MUTATION = gql`
       description
       occurredAt
       createdAt
-      createdBy
+      fromLocation {
+        name
+      }
+      toLocation {
+        name
+      }
     }
   }
 `;
@@ -92,19 +149,20 @@ type VehicleDetail = {
     modelCode: string;
     tractionType: string;
     releaseYear: number;
+    releaseYearMismatch: boolean;
+    manufacturer?: string | null;
+    currentLocation?: LocationOption | null;
     batchNumber: string;
     color?: string | null;
     mileage: number;
     status: string;
-    movements: MovementLogItem[];
+    movements: { edges: { node: MovementLogItem }[]; totalCount: number };
 };
 
 interface VehicleQueryResult {
     vehicle: VehicleDetail | null;
 }
 
-const statusOptions = ["ACTIVE", "INACTIVE", "DISCONTINUED"];
-
 export const VehicleDetailPage: React.FC = () => {
     const { id } = useParams<{ id: string }>();
     const navigate = useNavigate();
@@ -112,11 +170,10 @@ export const VehicleDetailPage: React.FC = () => {
     const [quickForm, setQuickForm] = useState({
         color: "",
         mileage: "",
-        status: "ACTIVE",
     });
 
     const { data, loading, error, refetch } = useQuery<VehicleQueryResult>(VEHICLE_QUERY, {
-        variables: { id, movementsLimit: 20, movementsOffset: 0 },
+        variables: { id, movementsFirst: 20, withCreatedBy: can(user, "user:read") },
         skip: !id,
         fetchPolicy: "network-only",
     });
@@ -124,6 +181,8 @@ export const VehicleDetailPage: React.FC = () => {
     const [updateVehicle, updateState] = useMutation(UPDATE_VEHICLE_MUTATION);
     const [deleteVehicle, deleteState] = useMutation<boolean>(DELETE_VEHICLE_MUTATION);
     const [createMovement, movementState] = useMutation(CREATE_MOVEMENT_MUTATION);
+    const { data: locationData } = useQuery<{ locations: LocationOption[] }>(LOCATIONS_QUERY);
+    const { data: movementTypeData } = useQuery<{ movementTypes: MovementTypeOption[] }>(MOVEMENT_TYPES_QUERY);
     const [confirmOpen, setConfirmOpen] = useState(false);
 
     const vehicle = data?.vehicle ?? null;
@@ -133,12 +192,12 @@ export const VehicleDetailPage: React.FC = () => {
         setQuickForm({
             color: vehicle.color ?? "",
             mileage: vehicle.mileage.toString(),
-            status: vehicle.status,
         });
     }, [vehicle?.id]);
 
-    const canEdit = user?.role.name !== "Viewer";
-    const canDelete = user?.role.name === "Admin";
+    const canEdit = can(user, "vehicle:update");
+    const canDelete = can(user, "vehicle:delete");
+    const canRecordMovement = can(user, "movement:create");
 
     const handleChange = (e: React.ChangeEvent<HTMLInputElement>) => {
         setQuickForm((prev) => ({ ...prev, [e.target.name]: e.target.value }));
@@ -153,7 +212,6 @@ export const VehicleDetailPage: React.FC = () => {
                 input: {
                     color: quickForm.color || null,
                     mileage: Number(quickForm.mileage),
-                    status: quickForm.status,
                 },
             },
         });
@@ -169,7 +227,7 @@ export const VehicleDetailPage: React.FC = () => {
     };
 
     const handleCreateMovement = async (values: MovementFormValues) => {
-        if (!id || !canEdit) return;
+        if (!id || !canRecordMovement) return;
         await createMovement({
             variables: {
                 input: {
@@ -177,13 +235,14 @@ export const VehicleDetailPage: React.FC = () => {
                     type: values.type,
                     description: values.description || null,
                     occurredAt: new Date(values.occurredAt).toISOString(),
+                    toLocationId: values.toLocationId || null,
                 },
             },
         });
         await refetch();
     };
 
-    const movementRows = useMemo(() => vehicle?.movements ?? [], [vehicle?.movements]);
+    const movementRows = useMemo(() => vehicle?.movements.edges.map((e) => e.node) ?? [], [vehicle?.movements]);
 
     if (!id) {
         return <Typography>Missing vehicle id.</Typography>;
@@ -260,7 +319,26 @@ export const VehicleDetailPage: React.FC = () => {
                         <Typography variant="body2" color="text.secondary">
                             Release Year
                         </Typography>
-                        <Typography>{vehicle.releaseYear}</Typography>
+                        <Typography color={vehicle.releaseYearMismatch ? "warning.main" : undefined}>
+                            {vehicle.releaseYear}
+                            {vehicle.releaseYearMismatch && " (does not match the VIN)"}
+                        </Typography>
+                    </Box>
+                    <Box>
+                        <Typography variant="body2" color="text.secondary">
+                            Manufacturer
+                        </Typography>
+                        <Typography>{vehicle.manufacturer ?? "-"}</Typography>
+                    </Box>
+                    <Box>
+                        <Typography variant="body2" color="text.secondary">
+                            Location
+                        </Typography>
+                        <Typography>
+                            {vehicle.currentLocation
+                                ? `${vehicle.currentLocation.name} (${vehicle.currentLocation.kind})`
+                                : "-"}
+                        </Typography>
                     </Box>
                     <Box>
                         <Typography variant="body2" color="text.secondary">
@@ -315,20 +393,6 @@ export const VehicleDetailPage: React.FC = () => {
                             required
                             sx={{ flex: "1 1 220px" }}
                         />
-                        <TextField
-                            select
-                            label="Status"
-                            name="status"
-                            value={quickForm.status}
-                            onChange={handleChange}
-                            sx={{ flex: "1 1 220px" }}
-                        >
-                            {statusOptions.map((option) => (
-                                <MenuItem key={option} value={option}>
-                                    {option}
-                                </MenuItem>
-                            ))}
-                        </TextField>
                         <Box sx={{ display: "flex", gap: 2, alignItems: "center", flexBasis: "100%" }}>
                             <Button type="submit" variant="contained" disabled={updateState.loading}>
                                 {updateState.loading ? "Saving..." : "Save Changes"}
@@ -344,7 +408,9 @@ export const VehicleDetailPage: React.FC = () => {
             <Paper sx={{ p: 3, display: "flex", flexDirection: "column", gap: 2 }}>
                 <MovementLogSection
                     movements={movementRows}
-                    canCreate={canEdit}
+                    locations={locationData?.locations ?? []}
+                    movementTypes={movementTypeData?.movementTypes ?? []}
+                    canCreate={canRecordMovement}
                     loading={movementState.loading}
                     onCreate={handleCreateMovement}
                 />
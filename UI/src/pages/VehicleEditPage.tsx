@@ -82,7 +82,6 @@ export const VehicleEditPage: React.FC = () => {
                     batchNumber: values.batchNumber,
                     color: values.color || null,
                     mileage: Number(values.mileage),
-                    status: values.status,
                 },
             },
         });
@@ -15,9 +15,13 @@ import {
 import { useNavigate } from "react-router-dom";
 import { useQuery } from "@apollo/client/react";
 import { useAuth } from "../auth/useAuth";
+import { can } from "../auth/AuthContext";
 
 export interface VehicleData {
-    vehicles: Vehicle[]
+    vehicles: {
+        edges: { node: Vehicle }[]
+        totalCount: number
+    }
 }
 
 export interface Vehicle {
@@ -31,15 +35,20 @@ export interface Vehicle {
 }
 
 const VEHICLES_QUERY = gql`
-  query Vehicles($limit: Int, $offset: Int) {
-    vehicles(limit: $limit, offset: $offset) {
-      id
-      vin
-      name
-      modelCode
-      tractionType
-      releaseYear
-      status
+  query Vehicles($first: Int, $after: String) {
+    vehicles(first: $first, after: $after) {
+      edges {
+        node {
+          id
+          vin
+          name
+          modelCode
+          tractionType
+          releaseYear
+          status
+        }
+      }
+      totalCount
     }
   }
 `;
@@ -47,11 +56,11 @@ const VEHICLES_QUERY = gql`
 export const VehiclesPage: React.FC = () => {
     
     const { data, loading, error, refetch } = useQuery<VehicleData>(VEHICLES_QUERY, {
-        variables: { limit: 20, offset: 0 },
+        variables: { first: 20 },
     });
     const navigate = useNavigate();
     const { user } = useAuth();
-    const canManage = user?.role.name !== "Viewer";
+    const canManage = can(user, "vehicle:create");
 
     if (loading) {
         return (
@@ -69,7 +78,7 @@ export const VehiclesPage: React.FC = () => {
         );
     }
 
-    const vehicles = data?.vehicles ?? [];
+    const vehicles = data?.vehicles.edges.map((e) => e.node) ?? [];
 
     return (
         <Box>
@@ -1,26 +1,33 @@
 package main
 
 import (
-	"fmt"
 	"log"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/config"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/db"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/export"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/graph"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/graph/loaders"
 	httpx "github.com/Kenfoxfire/Gear-Core-app/internal/http"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/webhook"
 
 	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func main() {
-	fmt.Print("Hello Kenneth")
 	cfg := config.Load()
 	dsn := db.DSN(cfg.DB.User, cfg.DB.Password, cfg.DB.Addr, cfg.DB.Database)
 	if cfg.DB.RunMigrations {
@@ -38,18 +45,60 @@ func main() {
 		log.Fatal(err)
 	}
 
-	repos := &domain.Repos{DB: pg}
-	authSvc := &domain.AuthService{Repos: repos, JWTSecret: []byte(cfg.App.JWTSecret)}
+	repos := &domain.Repos{DB: pg, Rules: domain.VehicleRules{
+		MinReleaseYear:       cfg.Validation.MinReleaseYear,
+		MaxReleaseYearsAhead: cfg.Validation.MaxReleaseYearsAhead,
+		VINCheckDigit:        cfg.Validation.VINCheckDigit,
+	}, MovementEditWindow: cfg.Movements.EditWindow}
+	authSvc := &domain.AuthService{
+		Repos: repos, JWTSecret: []byte(cfg.App.JWTSecret),
+		AccessTTL: cfg.Security.AccessTokenTTL, RefreshTTL: cfg.Security.RefreshTokenTTL,
+	}
+	authz := &domain.Authorizer{Repos: repos}
+	notifier := &domain.Notifier{DB: pg}
+	go notifier.Run(context.Background())
+	hooks := &webhook.Worker{
+		Repos: repos, Client: &http.Client{Timeout: cfg.Webhooks.Timeout},
+		Interval: cfg.Webhooks.PollInterval, MaxAttempts: cfg.Webhooks.MaxAttempts,
+		BaseBackoff: cfg.Webhooks.BaseBackoff, MaxBackoff: cfg.Webhooks.MaxBackoff,
+	}
+	go hooks.Run(context.Background())
 	res := &graph.Resolver{
-		DB: pg, Repos: repos, Auth: authSvc, JWTSecret: []byte(cfg.App.JWTSecret),
+		DB: pg, Repos: repos, Auth: authSvc, Authz: authz, Notifier: notifier,
+		Stats: &domain.FleetStatsCache{Repos: repos, TTL: cfg.Reports.FleetStatsTTL}, JWTSecret: []byte(cfg.App.JWTSecret),
 	}
 	router := chi.NewRouter()
 	router.Use(httpx.CORS(cfg.App.CORSAllowOrigins))
-	router.Use(httpx.AuthMiddleware([]byte(cfg.App.JWTSecret)))
+	router.Use(httpx.AuthMiddleware([]byte(cfg.App.JWTSecret), authSvc.SessionRole))
+	router.Use(loaders.Middleware(repos))
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: res}))
+	schema := graph.NewExecutableSchema(graph.Config{Resolvers: res, Directives: graph.Directives(authz)})
+	if err := graph.CheckAccessDirectives(schema.Schema()); err != nil {
+		log.Fatalf("schema self-check: %v", err)
+	}
+	srv := handler.New(schema)
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              httpx.WebsocketInit([]byte(cfg.App.JWTSecret), authSvc.SessionRole),
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: httpx.CheckOrigin(cfg.App.CORSAllowOrigins),
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](100)})
+	srv.AroundResponses(loaders.PerEvent(repos))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	srv.SetRecoverFunc(graph.Recover)
 
 	router.Handle("/query", srv)
+	exports := &export.Handler{Repos: repos, Authz: authz}
+	router.Get("/export/vehicles", exports.Vehicles)
+	router.Get("/export/movements", exports.Movements)
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		playground.Handler("GraphQL", "/query").ServeHTTP(w, r)
 	})
@@ -0,0 +1,104 @@
+// Package apperr defines the error kinds the API reports to clients. Each kind maps to a stable
+// code the GraphQL layer exposes as extensions.code; anything else is treated as an internal error.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code is the machine-readable kind of an Error.
+type Code string
+
+const (
+	CodeNotFound          Code = "NOT_FOUND"
+	CodeForbidden         Code = "FORBIDDEN"
+	CodeUnauthenticated   Code = "UNAUTHENTICATED"
+	CodeConflict          Code = "CONFLICT"
+	CodeValidation        Code = "VALIDATION_FAILED"
+	CodeIllegalTransition Code = "ILLEGAL_TRANSITION"
+	CodeInternal          Code = "INTERNAL"
+)
+
+// FieldError is one invalid input value. Path locates it inside the input, e.g. ["input", "vin"].
+type FieldError struct {
+	Path    []string
+	Message string
+}
+
+func (f FieldError) String() string {
+	return strings.Join(f.Path, ".") + " " + f.Message
+}
+
+// Error is an error safe to show to the client.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError // only for CodeValidation
+	Err     error        // optional cause, never shown
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches any *Error with the same code, so errors.Is(err, apperr.ErrNotFound) works for
+// every not-found error regardless of its message.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Sentinels for errors.Is checks.
+var (
+	ErrNotFound        = &Error{Code: CodeNotFound, Message: "not found"}
+	ErrForbidden       = &Error{Code: CodeForbidden, Message: "forbidden"}
+	ErrUnauthenticated = &Error{Code: CodeUnauthenticated, Message: "unauthenticated"}
+	ErrConflict        = &Error{Code: CodeConflict, Message: "conflict"}
+	ErrValidation      = &Error{Code: CodeValidation, Message: "validation failed"}
+)
+
+func NotFound(format string, args ...any) *Error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Forbidden(format string, args ...any) *Error {
+	return &Error{Code: CodeForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+func Unauthenticated(format string, args ...any) *Error {
+	return &Error{Code: CodeUnauthenticated, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) *Error {
+	return &Error{Code: CodeConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Invalid reports a single bad input value at path.
+func Invalid(path, format string, args ...any) *Error {
+	return Validation(FieldError{Path: strings.Split(path, "."), Message: fmt.Sprintf(format, args...)})
+}
+
+// Validation reports one or more bad input values. The message summarises them for clients
+// that ignore the field list.
+func Validation(fields ...FieldError) *Error {
+	msgs := make([]string, len(fields))
+	for i, f := range fields {
+		msgs[i] = f.String()
+	}
+	return &Error{Code: CodeValidation, Message: "invalid input: " + strings.Join(msgs, "; "), Fields: fields}
+}
+
+// Internal wraps an unexpected failure. Its message is generic; err is kept for logging.
+func Internal(err error) *Error {
+	return &Error{Code: CodeInternal, Message: "internal error", Err: err}
+}
+
+// CodeOf returns the code of the first *Error in err's chain, or CodeInternal.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -22,12 +23,36 @@ type DB struct {
 	RunMigrations bool   `mapstructure:"run_migrations"`
 }
 type Security struct {
-	AdminPassword string `mapstructure:"admin_password"`
+	AdminPassword   string        `mapstructure:"admin_password"`
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+}
+type Validation struct {
+	MinReleaseYear       int  `mapstructure:"min_release_year"`
+	MaxReleaseYearsAhead int  `mapstructure:"max_release_years_ahead"`
+	VINCheckDigit        bool `mapstructure:"vin_check_digit"`
+}
+type Webhooks struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	BaseBackoff  time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+}
+type Reports struct {
+	FleetStatsTTL time.Duration `mapstructure:"fleet_stats_ttl"`
+}
+type Movements struct {
+	EditWindow time.Duration `mapstructure:"edit_window"`
 }
 type Config struct {
-	App      App      `mapstructure:"app"`
-	DB       DB       `mapstructure:"db"`
-	Security Security `mapstructure:"security"`
+	App        App        `mapstructure:"app"`
+	DB         DB         `mapstructure:"db"`
+	Security   Security   `mapstructure:"security"`
+	Validation Validation `mapstructure:"validation"`
+	Webhooks   Webhooks   `mapstructure:"webhooks"`
+	Reports    Reports    `mapstructure:"reports"`
+	Movements  Movements  `mapstructure:"movements"`
 }
 
 func Load() Config {
@@ -39,6 +64,18 @@ func Load() Config {
 	v.AddConfigPath("../..")
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv() // Recognize auto Bind Env Variable
+	v.SetDefault("security.access_token_ttl", "15m")
+	v.SetDefault("security.refresh_token_ttl", "720h")
+	v.SetDefault("validation.min_release_year", 1886)
+	v.SetDefault("validation.max_release_years_ahead", 1)
+	v.SetDefault("validation.vin_check_digit", true)
+	v.SetDefault("webhooks.poll_interval", "2s")
+	v.SetDefault("webhooks.timeout", "10s")
+	v.SetDefault("webhooks.max_attempts", 8)
+	v.SetDefault("webhooks.base_backoff", "30s")
+	v.SetDefault("webhooks.max_backoff", "6h")
+	v.SetDefault("reports.fleet_stats_ttl", "1m")
+	v.SetDefault("movements.edit_window", "24h")
 
 	if err := v.ReadInConfig(); err != nil {
 		log.Fatalf("config read: %v", err)
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_movements_vehicle_occurred_id;
+DROP INDEX IF EXISTS idx_users_created_id;
+DROP INDEX IF EXISTS idx_vehicles_created_id;
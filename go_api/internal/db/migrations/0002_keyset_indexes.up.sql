@@ -0,0 +1,3 @@
+CREATE INDEX idx_vehicles_created_id ON vehicles(created_at DESC, id DESC);
+CREATE INDEX idx_users_created_id ON users(created_at DESC, id DESC);
+CREATE INDEX idx_movements_vehicle_occurred_id ON movements(vehicle_id, occurred_at DESC, id DESC);
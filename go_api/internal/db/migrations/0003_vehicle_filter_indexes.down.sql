@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_vehicles_batch;
+DROP INDEX IF EXISTS idx_vehicles_status;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_vehicles_status ON vehicles(status);
+CREATE INDEX idx_vehicles_batch ON vehicles(batch_number);
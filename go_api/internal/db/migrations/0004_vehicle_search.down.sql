@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_vehicles_batch_trgm;
+DROP INDEX IF EXISTS idx_vehicles_model_code_trgm;
+DROP INDEX IF EXISTS idx_vehicles_name_trgm;
+DROP INDEX IF EXISTS idx_vehicles_vin_trgm;
+DROP INDEX IF EXISTS idx_vehicles_search;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,14 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE vehicles ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', coalesce(vin, '')), 'A') ||
+  setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+  setweight(to_tsvector('simple', coalesce(model_code, '')), 'B') ||
+  setweight(to_tsvector('simple', coalesce(batch_number, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_vehicles_search ON vehicles USING GIN (search_vector);
+CREATE INDEX idx_vehicles_vin_trgm ON vehicles USING GIN (vin gin_trgm_ops);
+CREATE INDEX idx_vehicles_name_trgm ON vehicles USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_vehicles_model_code_trgm ON vehicles USING GIN (model_code gin_trgm_ops);
+CREATE INDEX idx_vehicles_batch_trgm ON vehicles USING GIN (batch_number gin_trgm_ops);
@@ -0,0 +1,12 @@
+CREATE TABLE vehicle_audit (
+  id BIGSERIAL PRIMARY KEY,
+  vehicle_id BIGINT NOT NULL,
+  action TEXT NOT NULL,
+  actor_id BIGINT REFERENCES users(id),
+  changes JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_vehicle_audit_vehicle ON vehicle_audit(vehicle_id, created_at DESC, id DESC);
+CREATE INDEX idx_vehicle_audit_created ON vehicle_audit(created_at DESC, id DESC);
+CREATE INDEX idx_vehicle_audit_actor ON vehicle_audit(actor_id);
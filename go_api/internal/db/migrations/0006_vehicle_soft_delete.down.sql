@@ -0,0 +1,6 @@
+ALTER TABLE movements DROP CONSTRAINT movements_vehicle_id_fkey;
+ALTER TABLE movements ADD CONSTRAINT movements_vehicle_id_fkey
+  FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_vehicles_deleted;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS deleted_at;
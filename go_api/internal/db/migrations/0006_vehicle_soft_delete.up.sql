@@ -0,0 +1,7 @@
+ALTER TABLE vehicles ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_vehicles_deleted ON vehicles(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Movements are only removed by an explicit purge, never as a side effect of deleting a vehicle.
+ALTER TABLE movements DROP CONSTRAINT movements_vehicle_id_fkey;
+ALTER TABLE movements ADD CONSTRAINT movements_vehicle_id_fkey
+  FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE RESTRICT;
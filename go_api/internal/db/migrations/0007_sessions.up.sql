@@ -0,0 +1,13 @@
+CREATE TABLE sessions (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  refresh_hash TEXT UNIQUE NOT NULL,
+  previous_hash TEXT,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  rotated_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_sessions_user ON sessions(user_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_sessions_previous_hash ON sessions(previous_hash);
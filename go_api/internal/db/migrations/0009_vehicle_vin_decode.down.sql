@@ -0,0 +1,3 @@
+ALTER TABLE vehicles
+  DROP COLUMN IF EXISTS plant_code,
+  DROP COLUMN IF EXISTS manufacturer;
@@ -0,0 +1,3 @@
+ALTER TABLE vehicles
+  ADD COLUMN manufacturer TEXT NOT NULL DEFAULT '',
+  ADD COLUMN plant_code TEXT NOT NULL DEFAULT '';
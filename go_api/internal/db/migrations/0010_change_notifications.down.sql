@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS vehicles_notify_changed ON vehicles;
+DROP FUNCTION IF EXISTS notify_vehicle_changed();
+DROP TRIGGER IF EXISTS movements_notify_created ON movements;
+DROP FUNCTION IF EXISTS notify_movement_created();
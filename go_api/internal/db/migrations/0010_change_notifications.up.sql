@@ -0,0 +1,36 @@
+-- Row changes are announced with NOTIFY so every API replica can feed its GraphQL subscriptions.
+-- Payloads carry IDs only; listeners load the rows themselves. NOTIFY is delivered on commit.
+CREATE FUNCTION notify_movement_created() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('movement_created', json_build_object('id', NEW.id, 'vehicleId', NEW.vehicle_id)::text);
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movements_notify_created AFTER INSERT ON movements
+  FOR EACH ROW EXECUTE FUNCTION notify_movement_created();
+
+-- action uses the audit vocabulary: soft deletes and restores are updates of deleted_at.
+CREATE FUNCTION notify_vehicle_changed() RETURNS trigger AS $$
+DECLARE
+  action TEXT;
+  vid BIGINT;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    action := 'CREATE'; vid := NEW.id;
+  ELSIF TG_OP = 'DELETE' THEN
+    action := 'PURGE'; vid := OLD.id;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    action := 'DELETE'; vid := NEW.id;
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    action := 'RESTORE'; vid := NEW.id;
+  ELSE
+    action := 'UPDATE'; vid := NEW.id;
+  END IF;
+  PERFORM pg_notify('vehicle_changed', json_build_object('id', vid, 'action', action)::text);
+  RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER vehicles_notify_changed AFTER INSERT OR UPDATE OR DELETE ON vehicles
+  FOR EACH ROW EXECUTE FUNCTION notify_vehicle_changed();
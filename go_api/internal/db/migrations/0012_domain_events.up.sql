@@ -0,0 +1,14 @@
+-- Append-only log of domain changes, written in the same transaction as the change.
+-- Writers hold an advisory lock from drawing seq until commit, so seq order is commit order.
+CREATE TABLE domain_events (
+  seq BIGSERIAL PRIMARY KEY,
+  type TEXT NOT NULL,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id BIGINT NOT NULL,
+  actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_domain_events_aggregate ON domain_events(aggregate_type, aggregate_id, seq);
+CREATE INDEX idx_domain_events_type ON domain_events(type, seq);
@@ -0,0 +1,5 @@
+ALTER TABLE movements
+  DROP COLUMN IF EXISTS to_location_id,
+  DROP COLUMN IF EXISTS from_location_id;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS current_location_id;
+DROP TABLE IF EXISTS locations;
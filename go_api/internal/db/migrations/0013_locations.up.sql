@@ -0,0 +1,22 @@
+-- Places a vehicle can be: yards, dealers and plants. Vehicles point at where they are now;
+-- TRANSFER and SALE movements record where they came from and went to.
+CREATE TABLE locations (
+  id BIGSERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL CHECK (kind IN ('YARD','DEALER','PLANT')),
+  address TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX uq_locations_name ON locations(lower(name));
+
+ALTER TABLE vehicles ADD COLUMN current_location_id BIGINT REFERENCES locations(id);
+CREATE INDEX idx_vehicles_location ON vehicles(current_location_id, created_at DESC, id DESC)
+  WHERE current_location_id IS NOT NULL;
+
+ALTER TABLE movements
+  ADD COLUMN from_location_id BIGINT REFERENCES locations(id),
+  ADD COLUMN to_location_id BIGINT REFERENCES locations(id);
+CREATE INDEX idx_movements_from_location ON movements(from_location_id) WHERE from_location_id IS NOT NULL;
+CREATE INDEX idx_movements_to_location ON movements(to_location_id) WHERE to_location_id IS NOT NULL;
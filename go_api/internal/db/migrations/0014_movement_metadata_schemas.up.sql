@@ -0,0 +1,36 @@
+-- JSON Schemas that movement metadata must satisfy, one per movement type. The seeded schemas
+-- fix the types of the well-known keys and reject unknown ones, but require none, so clients that
+-- send no metadata keep working; Admins can tighten them.
+CREATE TABLE movement_metadata_schemas (
+  movement_type TEXT PRIMARY KEY,
+  schema JSONB NOT NULL,
+  updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO movement_metadata_schemas (movement_type, schema) VALUES
+('SALE', '{
+  "type": "object",
+  "properties": {
+    "price": {"type": "number", "minimum": 0},
+    "currency": {"type": "string", "pattern": "^[A-Z]{3}$", "description": "ISO 4217 code"},
+    "buyer": {"type": "string", "minLength": 1, "maxLength": 200}
+  },
+  "additionalProperties": false
+}'),
+('DEFECT', '{
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "minLength": 1, "maxLength": 50},
+    "severity": {"enum": ["LOW", "MEDIUM", "HIGH", "CRITICAL"]}
+  },
+  "additionalProperties": false
+}'),
+('TRANSFER', '{
+  "type": "object",
+  "properties": {
+    "from": {"type": "string", "maxLength": 200},
+    "to": {"type": "string", "maxLength": 200}
+  },
+  "additionalProperties": false
+}');
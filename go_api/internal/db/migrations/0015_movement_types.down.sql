@@ -0,0 +1,13 @@
+ALTER TABLE movements DROP CONSTRAINT IF EXISTS fk_movements_type;
+
+CREATE TABLE movement_metadata_schemas (
+  movement_type TEXT PRIMARY KEY,
+  schema JSONB NOT NULL,
+  updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+INSERT INTO movement_metadata_schemas (movement_type, schema, updated_by, updated_at)
+SELECT name, metadata_schema, updated_by, updated_at FROM movement_types
+WHERE built_in AND metadata_schema IS NOT NULL;
+
+DROP TABLE IF EXISTS movement_types;
@@ -0,0 +1,28 @@
+-- Movement types become data. Each carries its own rules: the vehicle statuses it may be recorded
+-- from, the status it leaves the vehicle in (NULL keeps the current one), whether it names a
+-- destination location, and the JSON Schema its metadata must satisfy.
+CREATE TABLE movement_types (
+  name TEXT PRIMARY KEY CHECK (name ~ '^[A-Z][A-Z0-9_]{0,39}$'),
+  description TEXT NOT NULL DEFAULT '',
+  from_statuses TEXT[] NOT NULL,
+  to_status TEXT,
+  location_rule TEXT NOT NULL DEFAULT 'NONE' CHECK (location_rule IN ('NONE','OPTIONAL','REQUIRED')),
+  metadata_schema JSONB,
+  built_in BOOLEAN NOT NULL DEFAULT false,
+  updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO movement_types (name, description, from_statuses, to_status, location_rule, built_in) VALUES
+  ('SALE', 'Vehicle sold', '{ACTIVE}', 'SOLD', 'OPTIONAL', true),
+  ('RETURN', 'Sold vehicle returned', '{SOLD}', 'ACTIVE', 'NONE', true),
+  ('DEFECT', 'Defect reported', '{ACTIVE,INACTIVE,SOLD}', NULL, 'NONE', true),
+  ('TRANSFER', 'Moved to another location', '{ACTIVE,INACTIVE}', NULL, 'REQUIRED', true),
+  ('DISCONTINUED', 'Taken out of the fleet', '{ACTIVE,INACTIVE}', 'DISCONTINUED', 'NONE', true);
+
+UPDATE movement_types t SET metadata_schema = s.schema, updated_by = s.updated_by, updated_at = s.updated_at
+FROM movement_metadata_schemas s WHERE s.movement_type = t.name;
+DROP TABLE movement_metadata_schemas;
+
+ALTER TABLE movements ADD CONSTRAINT fk_movements_type FOREIGN KEY (type) REFERENCES movement_types(name);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS movement_audit;
+DROP INDEX IF EXISTS idx_movements_reverses;
+ALTER TABLE movements
+  DROP COLUMN IF EXISTS updated_at,
+  DROP COLUMN IF EXISTS void_reason,
+  DROP COLUMN IF EXISTS voided_by,
+  DROP COLUMN IF EXISTS voided_at,
+  DROP COLUMN IF EXISTS reverses_id,
+  DROP COLUMN IF EXISTS to_status,
+  DROP COLUMN IF EXISTS from_status;
@@ -0,0 +1,32 @@
+-- Movements can be corrected: edited within a grace window, voided, or reversed by a
+-- compensating entry. from_status and to_status record the status change a movement made, so it
+-- can be undone; they are NULL for movements recorded before this migration.
+ALTER TABLE movements
+  ADD COLUMN from_status TEXT,
+  ADD COLUMN to_status TEXT,
+  ADD COLUMN reverses_id BIGINT REFERENCES movements(id),
+  ADD COLUMN voided_at TIMESTAMPTZ,
+  ADD COLUMN voided_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  ADD COLUMN void_reason TEXT,
+  ADD COLUMN updated_at TIMESTAMPTZ;
+UPDATE movements SET updated_at = created_at;
+ALTER TABLE movements
+  ALTER COLUMN updated_at SET NOT NULL,
+  ALTER COLUMN updated_at SET DEFAULT now();
+
+-- a movement is reversed at most once
+CREATE UNIQUE INDEX idx_movements_reverses ON movements(reverses_id) WHERE reverses_id IS NOT NULL;
+
+-- Original values of corrected movements. Like vehicle_audit, rows are kept when the movement
+-- is purged with its vehicle.
+CREATE TABLE movement_audit (
+  id BIGSERIAL PRIMARY KEY,
+  movement_id BIGINT NOT NULL,
+  action TEXT NOT NULL CHECK (action IN ('UPDATE','VOID','REVERSE')),
+  actor_id BIGINT REFERENCES users(id),
+  changes JSONB NOT NULL DEFAULT '{}',
+  reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_movement_audit_movement ON movement_audit(movement_id, created_at DESC, id DESC);
@@ -0,0 +1,3 @@
+DROP VIEW IF EXISTS batches;
+DROP INDEX IF EXISTS idx_movements_correlation;
+ALTER TABLE movements DROP COLUMN IF EXISTS correlation_id;
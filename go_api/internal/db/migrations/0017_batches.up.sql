@@ -0,0 +1,28 @@
+-- Movements recorded together by applyMovementToBatch share a correlation ID.
+ALTER TABLE movements ADD COLUMN correlation_id UUID;
+CREATE INDEX idx_movements_correlation ON movements(correlation_id) WHERE correlation_id IS NOT NULL;
+
+-- One row per batch number with aggregates over its vehicles that are not deleted. defects counts
+-- DEFECT movements the way reports do: void, reversed and reversing ones are left out.
+CREATE VIEW batches AS
+SELECT v.batch_number,
+  COUNT(*)::int AS vehicles,
+  (COUNT(*) FILTER (WHERE v.status = 'ACTIVE'))::int AS active,
+  (COUNT(*) FILTER (WHERE v.status = 'INACTIVE'))::int AS inactive,
+  (COUNT(*) FILTER (WHERE v.status = 'SOLD'))::int AS sold,
+  (COUNT(*) FILTER (WHERE v.status = 'DISCONTINUED'))::int AS discontinued,
+  array_agg(DISTINCT v.model_code ORDER BY v.model_code) AS model_codes,
+  AVG(v.mileage)::float8 AS average_mileage,
+  COALESCE(SUM(d.defects), 0)::int AS defects,
+  MIN(v.created_at) AS first_created_at,
+  MAX(v.created_at) AS last_created_at
+FROM vehicles v
+LEFT JOIN (
+  SELECT m.vehicle_id, COUNT(*) AS defects
+  FROM movements m
+  WHERE m.type = 'DEFECT' AND m.voided_at IS NULL AND m.reverses_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM movements rev WHERE rev.reverses_id = m.id)
+  GROUP BY m.vehicle_id
+) d ON d.vehicle_id = v.id
+WHERE v.deleted_at IS NULL
+GROUP BY v.batch_number;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS seeded_role_permissions;
@@ -0,0 +1,22 @@
+-- Default grants SeedBase has already made, one row per (role, permission), so that each default is
+-- granted once: defaults added later reach existing roles, and grants an Admin revoked stay revoked.
+CREATE TABLE seeded_role_permissions (
+  role_id BIGINT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  permission TEXT NOT NULL,
+  PRIMARY KEY (role_id, permission)
+);
+
+-- Until now SeedBase granted defaults only to roles without any grant, so a role that has grants
+-- was seeded with the defaults the permission model started with. Later defaults are left out and
+-- are granted on the next start.
+INSERT INTO seeded_role_permissions (role_id, permission)
+SELECT r.id, d.permission
+FROM roles r
+JOIN (VALUES
+  ('Editor', 'vehicle:read'), ('Editor', 'vehicle:create'), ('Editor', 'vehicle:update'),
+  ('Editor', 'vehicle:import'), ('Editor', 'vehicle:export'), ('Editor', 'movement:read'),
+  ('Editor', 'movement:create'), ('Editor', 'report:read'),
+  ('Viewer', 'vehicle:read'), ('Viewer', 'vehicle:export'), ('Viewer', 'movement:read'),
+  ('Viewer', 'report:read')
+) AS d(role, permission) ON d.role = r.name
+WHERE EXISTS (SELECT 1 FROM role_permissions rp WHERE rp.role_id = r.id);
@@ -0,0 +1,15 @@
+CREATE TABLE outbox (
+  id BIGSERIAL PRIMARY KEY,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  dispatched_at TIMESTAMPTZ
+);
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE dispatched_at IS NULL;
+
+-- Deliveries made since point at domain_events, so the restored constraint cannot be validated.
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_event_id_fkey;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_event_id_fkey
+  FOREIGN KEY (event_id) REFERENCES outbox(id) NOT VALID;
+
+DROP TABLE IF EXISTS webhook_dispatch_cursor;
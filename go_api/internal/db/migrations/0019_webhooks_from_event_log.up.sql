@@ -0,0 +1,24 @@
+-- Webhooks are fed from domain_events instead of a separate outbox. The worker remembers the last
+-- event it fanned out; domain_events seq order is commit order, so it never skips one.
+CREATE TABLE webhook_dispatch_cursor (
+  id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+  last_seq BIGINT NOT NULL
+);
+
+-- Everything logged so far also went through the outbox, so dispatching resumes after it. Outbox
+-- rows not dispatched yet become deliveries here rather than being lost.
+INSERT INTO webhook_dispatch_cursor (last_seq) SELECT COALESCE(MAX(seq), 0) FROM domain_events;
+
+INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, body)
+SELECT h.id, o.id, o.event_type,
+  json_build_object('createdAt', o.created_at, 'data', o.payload, 'id', o.id, 'type', o.event_type)::text
+FROM outbox o
+JOIN webhooks h ON h.active AND o.event_type = ANY(h.events)
+WHERE o.dispatched_at IS NULL;
+
+-- event_id is domain_events.seq from now on; earlier deliveries keep their outbox ids, hence NOT VALID.
+ALTER TABLE webhook_deliveries DROP CONSTRAINT webhook_deliveries_event_id_fkey;
+ALTER TABLE webhook_deliveries ADD CONSTRAINT webhook_deliveries_event_id_fkey
+  FOREIGN KEY (event_id) REFERENCES domain_events(seq) NOT VALID;
+
+DROP TABLE outbox;
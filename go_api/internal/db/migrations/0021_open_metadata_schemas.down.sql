@@ -0,0 +1,27 @@
+UPDATE movement_types SET metadata_schema = metadata_schema || '{"additionalProperties": false}'::jsonb
+WHERE name = 'SALE' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "price": {"type": "number", "minimum": 0},
+    "currency": {"type": "string", "pattern": "^[A-Z]{3}$", "description": "ISO 4217 code"},
+    "buyer": {"type": "string", "minLength": 1, "maxLength": 200}
+  }
+}'::jsonb;
+
+UPDATE movement_types SET metadata_schema = metadata_schema || '{"additionalProperties": false}'::jsonb
+WHERE name = 'DEFECT' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "minLength": 1, "maxLength": 50},
+    "severity": {"enum": ["LOW", "MEDIUM", "HIGH", "CRITICAL"]}
+  }
+}'::jsonb;
+
+UPDATE movement_types SET metadata_schema = metadata_schema || '{"additionalProperties": false}'::jsonb
+WHERE name = 'TRANSFER' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "from": {"type": "string", "maxLength": 200},
+    "to": {"type": "string", "maxLength": 200}
+  }
+}'::jsonb;
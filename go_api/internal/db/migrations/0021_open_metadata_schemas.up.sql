@@ -0,0 +1,34 @@
+-- The schemas seeded by 0014 rejected metadata keys they don't name, which broke clients that had
+-- been sending free-form metadata. Seeded schemas that no Admin has changed now accept extra keys;
+-- only the well-known ones are typed. Admins can still forbid others.
+
+UPDATE movement_types SET metadata_schema = metadata_schema - 'additionalProperties'
+WHERE name = 'SALE' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "price": {"type": "number", "minimum": 0},
+    "currency": {"type": "string", "pattern": "^[A-Z]{3}$", "description": "ISO 4217 code"},
+    "buyer": {"type": "string", "minLength": 1, "maxLength": 200}
+  },
+  "additionalProperties": false
+}'::jsonb;
+
+UPDATE movement_types SET metadata_schema = metadata_schema - 'additionalProperties'
+WHERE name = 'DEFECT' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "minLength": 1, "maxLength": 50},
+    "severity": {"enum": ["LOW", "MEDIUM", "HIGH", "CRITICAL"]}
+  },
+  "additionalProperties": false
+}'::jsonb;
+
+UPDATE movement_types SET metadata_schema = metadata_schema - 'additionalProperties'
+WHERE name = 'TRANSFER' AND metadata_schema = '{
+  "type": "object",
+  "properties": {
+    "from": {"type": "string", "maxLength": 200},
+    "to": {"type": "string", "maxLength": 200}
+  },
+  "additionalProperties": false
+}'::jsonb;
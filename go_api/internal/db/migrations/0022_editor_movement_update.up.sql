@@ -0,0 +1,4 @@
+-- Intentionally empty. SeedBase grants Editor movement:update on the next start, like any default added
+-- later: 0018 records only the original defaults as already seeded. The file stays so databases
+-- that ran an earlier version of it keep a continuous migration history.
+SELECT 1;
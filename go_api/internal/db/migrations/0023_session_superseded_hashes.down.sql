@@ -0,0 +1,12 @@
+ALTER TABLE sessions ADD COLUMN previous_hash TEXT;
+
+UPDATE sessions s SET previous_hash = h.hash
+FROM (
+  SELECT DISTINCT ON (session_id) session_id, hash
+  FROM session_superseded_hashes
+  ORDER BY session_id, superseded_at DESC
+) h
+WHERE h.session_id = s.id;
+
+CREATE INDEX idx_sessions_previous_hash ON sessions(previous_hash);
+DROP TABLE IF EXISTS session_superseded_hashes;
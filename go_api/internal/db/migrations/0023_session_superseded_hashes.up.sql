@@ -0,0 +1,18 @@
+-- Every refresh token a session has rotated away from, so presenting any of them, not only the
+-- last one, is recognised as reuse and revokes the session.
+CREATE TABLE session_superseded_hashes (
+  hash TEXT PRIMARY KEY,
+  session_id BIGINT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  superseded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_session_superseded_hashes_session ON session_superseded_hashes(session_id);
+
+INSERT INTO session_superseded_hashes (hash, session_id, superseded_at)
+SELECT previous_hash, id, COALESCE(rotated_at, created_at)
+FROM sessions
+WHERE previous_hash IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS idx_sessions_previous_hash;
+ALTER TABLE sessions DROP COLUMN previous_hash;
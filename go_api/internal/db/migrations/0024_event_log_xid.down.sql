@@ -0,0 +1,9 @@
+ALTER TABLE webhook_dispatch_cursor DROP COLUMN IF EXISTS last_xid;
+
+DROP INDEX IF EXISTS idx_domain_events_aggregate;
+DROP INDEX IF EXISTS idx_domain_events_type;
+DROP INDEX IF EXISTS idx_domain_events_xid;
+CREATE INDEX idx_domain_events_aggregate ON domain_events(aggregate_type, aggregate_id, seq);
+CREATE INDEX idx_domain_events_type ON domain_events(type, seq);
+
+ALTER TABLE domain_events DROP COLUMN IF EXISTS xid;
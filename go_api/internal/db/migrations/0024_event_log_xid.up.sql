@@ -0,0 +1,13 @@
+-- Writers no longer serialize on an advisory lock. Each event records the transaction that wrote
+-- it, and readers page by (xid, seq) over events whose transaction is older than every one still
+-- running, so an event that commits late can't be skipped. Earlier events keep xid 0 and seq order.
+ALTER TABLE domain_events ADD COLUMN xid xid8 NOT NULL DEFAULT '0';
+ALTER TABLE domain_events ALTER COLUMN xid SET DEFAULT pg_current_xact_id();
+
+CREATE INDEX idx_domain_events_xid ON domain_events(xid, seq);
+DROP INDEX IF EXISTS idx_domain_events_aggregate;
+DROP INDEX IF EXISTS idx_domain_events_type;
+CREATE INDEX idx_domain_events_aggregate ON domain_events(aggregate_type, aggregate_id, xid, seq);
+CREATE INDEX idx_domain_events_type ON domain_events(type, xid, seq);
+
+ALTER TABLE webhook_dispatch_cursor ADD COLUMN last_xid xid8 NOT NULL DEFAULT '0';
@@ -10,7 +10,15 @@ import (
 	"github.com/go-pg/pg/v10"
 )
 
+// SeedBase creates the built-in roles, permissions and grants and the admin user in one
+// transaction, so a failed start leaves nothing half-seeded.
 func SeedBase(ctx context.Context, db *pg.DB, adminPassword string) error {
+	return WithTx(ctx, db, func(tx *pg.Tx) error {
+		return seedBase(tx, adminPassword)
+	})
+}
+
+func seedBase(db pg.DBI, adminPassword string) error {
 	// Ensure roles
 	roles := []domain.Role{
 		{Name: domain.RoleAdmin},
@@ -27,6 +35,42 @@ func SeedBase(ctx context.Context, db *pg.DB, adminPassword string) error {
 		}
 	}
 
+	// Ensure permissions and the built-in grants. Admin always gets every permission; Editor and
+	// Viewer get each default once, recorded in seeded_role_permissions, so defaults added later
+	// reach existing databases while grants an Admin revoked stay revoked.
+	for i := range domain.AllPermissions {
+		p := domain.AllPermissions[i]
+		_, err := db.Model(&p).
+			OnConflict("(name) DO UPDATE").
+			Set("description = EXCLUDED.description").
+			Insert()
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := db.Exec(`
+	  INSERT INTO role_permissions (role_id, permission_id)
+	  SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = ?
+	  ON CONFLICT DO NOTHING`, domain.RoleAdmin); err != nil {
+		return err
+	}
+	for role, perms := range domain.DefaultRolePermissions {
+		_, err := db.Exec(`
+		  WITH pending AS (
+		    INSERT INTO seeded_role_permissions (role_id, permission)
+		    SELECT r.id, p.name FROM roles r JOIN permissions p ON p.name IN (?)
+		    WHERE r.name = ?
+		    ON CONFLICT DO NOTHING
+		    RETURNING role_id, permission)
+		  INSERT INTO role_permissions (role_id, permission_id)
+		  SELECT pending.role_id, p.id FROM pending JOIN permissions p ON p.name = pending.permission
+		  ON CONFLICT DO NOTHING`,
+			pg.In(perms), role)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Upsert main admin user
 	var adminRole domain.Role
 	if err := db.Model(&adminRole).Where("name = ?", domain.RoleAdmin).Select(); err != nil {
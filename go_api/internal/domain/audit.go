@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// Audit actions recorded for vehicles.
+const (
+	AuditCreate  = "CREATE"
+	AuditUpdate  = "UPDATE"
+	AuditDelete  = "DELETE"
+	AuditRestore = "RESTORE"
+	AuditPurge   = "PURGE"
+)
+
+// FieldChange holds the before and after value of one field. Old is nil on create, New on delete.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// VehicleAudit is one recorded change to a vehicle. Rows outlive the vehicle they describe.
+type VehicleAudit struct {
+	tableName struct{}               `pg:"vehicle_audit"`
+	ID        int64                  `pg:"id,pk"`
+	VehicleID int64                  `pg:"vehicle_id,notnull"`
+	Action    string                 `pg:"action,notnull"`
+	ActorID   int64                  `pg:"actor_id"` // 0 (NULL) when the change was not made by a signed-in user
+	Changes   map[string]FieldChange `pg:"changes,type:jsonb"`
+	CreatedAt time.Time              `pg:"created_at,default:now()"`
+}
+
+func auditCursor(a *VehicleAudit) Cursor { return timeCursor("created_at", true, a.CreatedAt, a.ID) }
+
+// vehicleFields lists the audited fields of v, keyed by their GraphQL names.
+func vehicleFields(v *Vehicle) map[string]any {
+	return map[string]any{
+		"vin": v.VIN, "name": v.Name, "modelCode": v.ModelCode, "tractionType": v.TractionType,
+		"releaseYear": v.ReleaseYear, "batchNumber": v.BatchNumber, "color": v.Color,
+		"mileage": v.Mileage, "status": v.Status, "manufacturer": v.Manufacturer, "plantCode": v.PlantCode,
+		"currentLocationId": optID(v.CurrentLocationID),
+	}
+}
+
+// optID is id, or nil when it is 0 (NULL).
+func optID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// diffVehicles returns the fields that differ between before and after; either may be nil.
+func diffVehicles(before, after *Vehicle) map[string]FieldChange {
+	var old, cur map[string]any
+	if before != nil {
+		old = vehicleFields(before)
+	}
+	if after != nil {
+		cur = vehicleFields(after)
+	}
+	changes := map[string]FieldChange{}
+	for _, fields := range []map[string]any{old, cur} {
+		for k := range fields {
+			if _, seen := changes[k]; seen {
+				continue
+			}
+			if o, n := old[k], cur[k]; o != n {
+				changes[k] = FieldChange{Old: o, New: n}
+			}
+		}
+	}
+	return changes
+}
+
+// recordVehicleAudit appends an audit row unless nothing changed.
+func (r *Repos) recordVehicleAudit(vehicleID int64, action string, actorID int64, before, after *Vehicle) error {
+	var changes map[string]FieldChange
+	switch action {
+	case AuditRestore:
+		changes = map[string]FieldChange{}
+	default:
+		changes = diffVehicles(before, after)
+	}
+	if action == AuditUpdate && len(changes) == 0 {
+		return nil
+	}
+	_, err := r.DB.Model(&VehicleAudit{
+		VehicleID: vehicleID, Action: action, ActorID: actorID, Changes: changes,
+	}).Insert()
+	return err
+}
+
+// AuditFilter narrows the audit log. Nil or empty fields are ignored.
+type AuditFilter struct {
+	VehicleID *int64
+	ActorID   *int64
+	Actions   []string
+	From      *time.Time
+	To        *time.Time
+}
+
+func (f AuditFilter) Where(q *orm.Query) (*orm.Query, error) {
+	if f.VehicleID != nil {
+		q = q.Where("vehicle_id = ?", *f.VehicleID)
+	}
+	if f.ActorID != nil {
+		q = q.Where("actor_id = ?", *f.ActorID)
+	}
+	if len(f.Actions) > 0 {
+		q = q.Where("action IN (?)", pg.In(f.Actions))
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	return q, nil
+}
+
+// ListVehicleAudit returns matching audit rows newest first, using (created_at, id) as the keyset.
+func (r *Repos) ListVehicleAudit(ctx context.Context, filter AuditFilter, page PageArgs) (*Page[*VehicleAudit], error) {
+	var items []*VehicleAudit
+	q := r.DB.Model(&items).Apply(filter.Where)
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = keyset(q, page.After, "created_at", true); err != nil {
+		return nil, err
+	}
+	limit := page.Limit()
+	err = q.Order("created_at DESC", "id DESC").Limit(limit + 1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return newPage(items, limit, total, auditCursor), nil
+}
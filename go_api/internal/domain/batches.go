@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/google/uuid"
+)
+
+// MaxBatchVehicles caps how many vehicles one batch operation may change.
+const MaxBatchVehicles = 1000
+
+// Batch summarizes the vehicles sharing a batch number, read from the batches view. Deleted
+// vehicles are left out, so a batch whose vehicles are all deleted does not exist.
+type Batch struct {
+	tableName      struct{}  `pg:"batches"`
+	Number         string    `pg:"batch_number"`
+	Vehicles       int       `pg:"vehicles"`
+	Active         int       `pg:"active"`
+	Inactive       int       `pg:"inactive"`
+	Sold           int       `pg:"sold"`
+	Discontinued   int       `pg:"discontinued"`
+	ModelCodes     []string  `pg:"model_codes,array"`
+	AverageMileage float64   `pg:"average_mileage"`
+	Defects        int       `pg:"defects"` // counted like MovementReport counts
+	FirstCreatedAt time.Time `pg:"first_created_at"`
+	LastCreatedAt  time.Time `pg:"last_created_at"`
+}
+
+// ByStatus counts the batch's vehicles per status, leaving out statuses without any.
+func (b *Batch) ByStatus() []KeyCount {
+	var out []KeyCount
+	for _, c := range []KeyCount{
+		{StatusActive, b.Active}, {StatusInactive, b.Inactive},
+		{StatusSold, b.Sold}, {StatusDiscontinued, b.Discontinued},
+	} {
+		if c.Count > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func batchCursor(b *Batch) Cursor { return Cursor{Key: "batch_number", Value: b.Number} }
+
+// ListBatches returns batches by number, optionally only those whose number contains query
+// (ignoring case), using the number as the keyset.
+func (r *Repos) ListBatches(ctx context.Context, query string, page PageArgs) (*Page[*Batch], error) {
+	var items []*Batch
+	q := r.DB.ModelContext(ctx, &items)
+	if query = strings.TrimSpace(query); query != "" {
+		q = q.Where("batch_number ILIKE ?", containsPattern(query))
+	}
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if c := page.After; c != nil {
+		if c.Key != "batch_number" || c.Desc {
+			return nil, ErrInvalidCursor
+		}
+		q = q.Where("batch_number > ?", c.Value)
+	}
+	limit := page.Limit()
+	if err := q.Order("batch_number ASC").Limit(limit + 1).Select(); err != nil {
+		return nil, err
+	}
+	return newPage(items, limit, total, batchCursor), nil
+}
+
+func (r *Repos) GetBatch(ctx context.Context, number string) (*Batch, error) {
+	var b Batch
+	if err := r.DB.ModelContext(ctx, &b).Where("batch_number = ?", number).Select(); err != nil {
+		return nil, notFound(err, "batch %s not found", number)
+	}
+	return &b, nil
+}
+
+// lockBatch locks the vehicles of a batch in ID order, so concurrent batch operations and
+// RecordMovement calls cannot deadlock on them, and returns them in that order.
+func (r *Repos) lockBatch(ctx context.Context, number string) ([]*Vehicle, error) {
+	var vs []*Vehicle
+	err := r.DB.ModelContext(ctx, &vs).Where("batch_number = ?", number).
+		Order("id ASC").Limit(MaxBatchVehicles + 1).For("UPDATE").Select()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(vs) == 0:
+		return nil, apperr.NotFound("batch %s not found", number)
+	case len(vs) > MaxBatchVehicles:
+		return nil, apperr.Invalid("batchNumber", "batch %s has more than %d vehicles", number, MaxBatchVehicles)
+	}
+	return vs, nil
+}
+
+// forVehicle attributes err, raised while changing one vehicle of a batch, to that vehicle.
+func forVehicle(v *Vehicle, err error) error {
+	var te *TransitionError
+	if errors.As(err, &te) {
+		te.VIN = v.VIN
+		return err
+	}
+	var ae *apperr.Error
+	if errors.As(err, &ae) && ae.Code == apperr.CodeValidation {
+		fields := make([]apperr.FieldError, len(ae.Fields))
+		for i, f := range ae.Fields {
+			fields[i] = apperr.FieldError{Path: f.Path, Message: fmt.Sprintf("vehicle %s: %s", v.VIN, f.Message)}
+		}
+		return apperr.Validation(fields...)
+	}
+	return err
+}
+
+// ApplyMovementToBatch records a copy of m on every vehicle of a batch, all in one transaction
+// under a fresh correlation ID, which it returns with the movements. m.VehicleID is ignored.
+// If any vehicle rejects the movement, none is recorded.
+func (r *Repos) ApplyMovementToBatch(ctx context.Context, number string, m *Movement) (string, []*Movement, error) {
+	correlationID := uuid.NewString()
+	var out []*Movement
+	err := r.InTx(ctx, func(tx *Repos) error {
+		vs, err := tx.lockBatch(ctx, number)
+		if err != nil {
+			return err
+		}
+		out = make([]*Movement, 0, len(vs))
+		for _, v := range vs {
+			vm := *m
+			vm.VehicleID, vm.Vehicle, vm.CorrelationID = v.ID, nil, correlationID
+			if _, err := tx.RecordMovement(ctx, &vm); err != nil {
+				return forVehicle(v, err)
+			}
+			out = append(out, &vm)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return correlationID, out, nil
+}
+
+// BatchVehicleChanges lists the fields UpdateVehiclesInBatch sets. Nil fields are left as they
+// are. Per-vehicle fields such as name, mileage and status are not included; status changes go
+// through movements.
+type BatchVehicleChanges struct {
+	ModelCode    *string
+	TractionType *string
+	ReleaseYear  *int
+	BatchNumber  *string
+	Color        *string
+	Manufacturer *string
+	PlantCode    *string
+}
+
+func (c BatchVehicleChanges) apply(v *Vehicle) {
+	VehicleChanges{
+		ModelCode: c.ModelCode, TractionType: c.TractionType, ReleaseYear: c.ReleaseYear,
+		BatchNumber: c.BatchNumber, Color: c.Color, Manufacturer: c.Manufacturer, PlantCode: c.PlantCode,
+	}.apply(v)
+}
+
+// UpdateVehiclesInBatch applies c to every vehicle of a batch in one transaction, validating and
+// auditing each like UpdateVehicle, and returns the updated vehicles. If any vehicle fails
+// validation, none is changed. c.BatchNumber renames the batch, soft-deleted vehicles included so
+// restoring one does not bring the old batch back, and must not be used by any other vehicle,
+// deleted or not.
+func (r *Repos) UpdateVehiclesInBatch(ctx context.Context, number string, c BatchVehicleChanges, actorID int64) ([]*Vehicle, error) {
+	var vs []*Vehicle
+	err := r.InTx(ctx, func(tx *Repos) error {
+		var err error
+		if vs, err = tx.lockBatch(ctx, number); err != nil {
+			return err
+		}
+		rename := c.BatchNumber != nil && *c.BatchNumber != number
+		if rename {
+			taken, err := tx.DB.ModelContext(ctx, (*Vehicle)(nil)).
+				Where("batch_number = ?", *c.BatchNumber).AllWithDeleted().Exists()
+			if err != nil {
+				return err
+			}
+			if taken {
+				return apperr.Invalid("batchNumber", "batch %s already exists; batches cannot be merged", *c.BatchNumber)
+			}
+		}
+		for _, v := range vs {
+			before := *v
+			c.apply(v)
+			if err := tx.saveVehicle(ctx, &before, v, actorID); err != nil {
+				return forVehicle(v, err)
+			}
+		}
+		if !rename {
+			return nil
+		}
+		// Deleted vehicles only follow the rename; the other fields describe the live batch.
+		var deleted []*Vehicle
+		err = tx.DB.ModelContext(ctx, &deleted).Where("batch_number = ? AND deleted_at IS NOT NULL", number).
+			AllWithDeleted().Order("id ASC").For("UPDATE").Select()
+		if err != nil {
+			return err
+		}
+		for _, v := range deleted {
+			before := *v
+			v.BatchNumber = *c.BatchNumber
+			if err := tx.saveVehicle(ctx, &before, v, actorID); err != nil {
+				return forVehicle(v, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+)
+
+// notFound turns pg.ErrNoRows into a NOT_FOUND error with the given message, keeping the
+// original as its cause. Other errors pass through unchanged.
+func notFound(err error, format string, args ...any) error {
+	if errors.Is(err, pg.ErrNoRows) {
+		return &apperr.Error{Code: apperr.CodeNotFound, Message: fmt.Sprintf(format, args...), Err: err}
+	}
+	return err
+}
+
+// conflict turns a unique-constraint violation into a CONFLICT error with the given message.
+// Other errors pass through unchanged.
+func conflict(err error, format string, args ...any) error {
+	var pgErr pg.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == "23505" {
+		return &apperr.Error{Code: apperr.CodeConflict, Message: fmt.Sprintf(format, args...), Err: err}
+	}
+	return err
+}
+
+// inUse turns a foreign-key violation, such as deleting a row that others still reference, into
+// a CONFLICT error with the given message. Other errors pass through unchanged.
+func inUse(err error, format string, args ...any) error {
+	var pgErr pg.Error
+	if errors.As(err, &pgErr) && pgErr.Field('C') == "23503" {
+		return &apperr.Error{Code: apperr.CodeConflict, Message: fmt.Sprintf(format, args...), Err: err}
+	}
+	return err
+}
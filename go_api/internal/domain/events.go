@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// Domain event types recorded in the event log.
+const (
+	EventVehicleCreated  = "VEHICLE_CREATED"
+	EventVehicleDeleted  = "VEHICLE_DELETED"
+	EventVehicleRestored = "VEHICLE_RESTORED"
+	EventVehiclePurged   = "VEHICLE_PURGED"
+	EventMovementCreated = "MOVEMENT_CREATED"
+	// Corrections, recorded against the corrected movement. A reversal is also MOVEMENT_CREATED.
+	EventMovementUpdated  = "MOVEMENT_UPDATED"
+	EventMovementVoided   = "MOVEMENT_VOIDED"
+	EventMovementReversed = "MOVEMENT_REVERSED"
+	EventUserRoleChanged  = "USER_ROLE_CHANGED"
+	// EventVehicleUpdated is shared with webhooks.
+)
+
+// Aggregates an event can be about.
+const (
+	AggregateVehicle  = "vehicle"
+	AggregateMovement = "movement"
+	AggregateUser     = "user"
+)
+
+// DomainEvent is one entry of the append-only event log. XID is the writing transaction; the log
+// is read in (XID, Seq) order and only up to the oldest transaction still running (see
+// settledEvents), so a reader that remembers the last position it saw never misses an event.
+type DomainEvent struct {
+	tableName     struct{}       `pg:"domain_events"`
+	Seq           int64          `pg:"seq,pk"`
+	XID           int64          `pg:"xid,default:pg_current_xact_id()"`
+	Type          string         `pg:"type,notnull"`
+	AggregateType string         `pg:"aggregate_type,notnull"`
+	AggregateID   int64          `pg:"aggregate_id,notnull"`
+	ActorID       int64          `pg:"actor_id"` // 0 (NULL) when the change was not made by a signed-in user
+	Payload       map[string]any `pg:"payload,type:jsonb"`
+	CreatedAt     time.Time      `pg:"created_at,default:now()"`
+}
+
+// recordEvent appends an event. It must run inside the transaction making the change, so the
+// event commits or rolls back with it. Rolled-back transactions leave gaps in Seq.
+func (r *Repos) recordEvent(ctx context.Context, e *DomainEvent) error {
+	_, err := r.DB.ModelContext(ctx, e).Insert()
+	return err
+}
+
+// settledEvents restricts q to events after (xid, seq) whose transaction is older than every
+// transaction still running. Those have all committed or rolled back, and any event written later
+// sorts after them, so paging in (xid, seq) order skips nothing. A long-running transaction holds
+// the log back until it ends.
+func settledEvents(q *orm.Query, xid, seq int64) *orm.Query {
+	return q.Where("xid < pg_snapshot_xmin(pg_current_snapshot())").
+		Where("(xid, seq) > (?::xid8, ?)", strconv.FormatInt(xid, 10), seq).
+		Order("xid ASC", "seq ASC")
+}
+
+func (r *Repos) recordVehicleEvent(ctx context.Context, eventType string, v *Vehicle, actorID int64, changes map[string]FieldChange) error {
+	return r.recordEvent(ctx, &DomainEvent{
+		Type: eventType, AggregateType: AggregateVehicle, AggregateID: v.ID, ActorID: actorID,
+		Payload: vehiclePayload(v, changes),
+	})
+}
+
+func eventCursor(e *DomainEvent) Cursor {
+	return Cursor{Key: "xid_seq", Value: strconv.FormatInt(e.XID, 10), ID: e.Seq}
+}
+
+// EventFilter narrows the event log. Nil or empty fields are ignored.
+type EventFilter struct {
+	Types         []string
+	AggregateType *string
+	AggregateID   *int64
+}
+
+// ListEvents returns settled events after page.After in log order. It reports no total count, as
+// the log only grows; readers poll with the cursor of the last event they saw.
+func (r *Repos) ListEvents(ctx context.Context, filter EventFilter, page PageArgs) (*Page[*DomainEvent], error) {
+	var items []*DomainEvent
+	q := r.DB.ModelContext(ctx, &items)
+	if len(filter.Types) > 0 {
+		q = q.Where("type IN (?)", pg.In(filter.Types))
+	}
+	if filter.AggregateType != nil {
+		q = q.Where("aggregate_type = ?", *filter.AggregateType)
+	}
+	if filter.AggregateID != nil {
+		q = q.Where("aggregate_id = ?", *filter.AggregateID)
+	}
+	var xid, seq int64
+	if c := page.After; c != nil {
+		var err error
+		if xid, err = strconv.ParseInt(c.Value, 10, 64); err != nil || c.Key != "xid_seq" || c.Desc {
+			return nil, ErrInvalidCursor
+		}
+		seq = c.ID
+	}
+	limit := page.Limit()
+	if err := settledEvents(q, xid, seq).Limit(limit + 1).Select(); err != nil {
+		return nil, err
+	}
+	return newPage(items, limit, 0, eventCursor), nil
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// KeyCount is a number of vehicles sharing a column value.
+type KeyCount struct {
+	Key   string `pg:"key"`
+	Count int    `pg:"count"`
+}
+
+// ModelMileage is the average mileage of the vehicles of one model.
+type ModelMileage struct {
+	ModelCode      string  `pg:"model_code"`
+	Vehicles       int     `pg:"vehicles"`
+	AverageMileage float64 `pg:"average_mileage"`
+}
+
+// BatchDefects counts DEFECT movements against the vehicles of a batch. A vehicle may have
+// several defects, so the rate can exceed 1.
+type BatchDefects struct {
+	BatchNumber string `pg:"batch_number"`
+	Vehicles    int    `pg:"vehicles"`
+	Defects     int    `pg:"defects"`
+}
+
+func (b BatchDefects) Rate() float64 { return float64(b.Defects) / float64(b.Vehicles) }
+
+// FleetStats are the fleet KPIs. Soft-deleted vehicles and their movements are left out, as are
+// void, reversed and reversing movements.
+type FleetStats struct {
+	TotalVehicles     int
+	ByStatus          []KeyCount
+	ByTractionType    []KeyCount
+	MileageByModel    []ModelMileage
+	DefectsByBatch    []BatchDefects
+	Sold              int      // vehicles with at least one SALE
+	ReturnedAfterSale int      // sold vehicles with a RETURN after their first SALE
+	MeanDaysToSale    *float64 // from creation to first SALE; nil when nothing was sold
+	ComputedAt        time.Time
+}
+
+// ReturnRate is the share of sold vehicles returned afterwards, or nil when nothing was sold.
+func (s *FleetStats) ReturnRate() *float64 {
+	if s.Sold == 0 {
+		return nil
+	}
+	rate := float64(s.ReturnedAfterSale) / float64(s.Sold)
+	return &rate
+}
+
+// FleetStats computes the fleet KPIs in SQL. All queries read one snapshot, so the figures are
+// consistent with each other.
+func (r *Repos) FleetStats(ctx context.Context) (*FleetStats, error) {
+	s := &FleetStats{}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if _, err := tx.DB.ExecContext(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ READ ONLY"); err != nil {
+			return err
+		}
+		counts := func(dst *[]KeyCount, col string) error {
+			_, err := tx.DB.QueryContext(ctx, dst, `
+			  SELECT ?::text AS key, COUNT(*)::int AS count
+			  FROM vehicles WHERE deleted_at IS NULL
+			  GROUP BY 1 ORDER BY count DESC, key`, pg.Ident(col))
+			return err
+		}
+		if err := counts(&s.ByStatus, "status"); err != nil {
+			return err
+		}
+		if err := counts(&s.ByTractionType, "traction_type"); err != nil {
+			return err
+		}
+		for _, c := range s.ByStatus {
+			s.TotalVehicles += c.Count
+		}
+		if _, err := tx.DB.QueryContext(ctx, &s.MileageByModel, `
+		  SELECT model_code, COUNT(*)::int AS vehicles, AVG(mileage)::float8 AS average_mileage
+		  FROM vehicles WHERE deleted_at IS NULL
+		  GROUP BY model_code ORDER BY model_code`); err != nil {
+			return err
+		}
+		if _, err := tx.DB.QueryContext(ctx, &s.DefectsByBatch, `
+		  SELECT v.batch_number, COUNT(*)::int AS vehicles, COALESCE(SUM(d.defects), 0)::int AS defects
+		  FROM vehicles v
+		  LEFT JOIN (
+		    SELECT vehicle_id, COUNT(*) AS defects FROM movements m WHERE type = ? AND `+counted("m")+` GROUP BY vehicle_id
+		  ) d ON d.vehicle_id = v.id
+		  WHERE v.deleted_at IS NULL
+		  GROUP BY v.batch_number
+		  ORDER BY COALESCE(SUM(d.defects), 0)::float8 / COUNT(*) DESC, v.batch_number`, MoveDefect); err != nil {
+			return err
+		}
+		var sales struct {
+			Sold        int      `pg:"sold"`
+			Returned    int      `pg:"returned"`
+			MeanSeconds *float64 `pg:"mean_seconds"`
+		}
+		if _, err := tx.DB.QueryOneContext(ctx, &sales, `
+		  WITH sales AS (
+		    SELECT vehicle_id, MIN(occurred_at) AS sold_at FROM movements m WHERE type = ?0 AND `+counted("m")+`
+		    GROUP BY vehicle_id
+		  )
+		  SELECT COUNT(*)::int AS sold,
+		    (COUNT(*) FILTER (WHERE EXISTS (
+		      SELECT 1 FROM movements m
+		      WHERE m.vehicle_id = s.vehicle_id AND m.type = ?1 AND m.occurred_at > s.sold_at AND `+counted("m")+`
+		    )))::int AS returned,
+		    AVG(EXTRACT(EPOCH FROM s.sold_at - v.created_at))::float8 AS mean_seconds
+		  FROM sales s JOIN vehicles v ON v.id = s.vehicle_id AND v.deleted_at IS NULL`,
+			MoveSale, MoveReturn); err != nil {
+			return err
+		}
+		s.Sold, s.ReturnedAfterSale = sales.Sold, sales.Returned
+		if sales.MeanSeconds != nil {
+			days := *sales.MeanSeconds / (24 * 60 * 60)
+			s.MeanDaysToSale = &days
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.ComputedAt = time.Now()
+	return s, nil
+}
+
+// FleetStatsCache serves FleetStats, recomputing them at most once per TTL.
+type FleetStatsCache struct {
+	Repos *Repos
+	TTL   time.Duration
+
+	mu    sync.Mutex
+	stats *FleetStats
+}
+
+const defaultFleetStatsTTL = time.Minute
+
+// Get returns the cached stats, recomputing them once they are older than TTL. Concurrent
+// callers wait for a single recomputation.
+func (c *FleetStatsCache) Get(ctx context.Context) (*FleetStats, error) {
+	ttl := c.TTL
+	if ttl == 0 {
+		ttl = defaultFleetStatsTTL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stats != nil && time.Since(c.stats.ComputedAt) < ttl {
+		return c.stats, nil
+	}
+	stats, err := c.Repos.FleetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.stats = stats
+	return stats, nil
+}
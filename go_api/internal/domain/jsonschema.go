@@ -0,0 +1,307 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// jsonSchema is a compiled JSON Schema. Only the keywords below are supported; compileSchema
+// rejects any other, so a schema never appears to enforce something it does not.
+//
+//	type (a name or a list of names), enum, const
+//	properties, required, additionalProperties (a boolean)
+//	items, minItems, maxItems
+//	minimum, maximum, exclusiveMinimum, exclusiveMaximum
+//	minLength, maxLength, pattern
+//	title, description, $schema, $comment, default, examples (annotations, ignored)
+type jsonSchema struct {
+	types                []string
+	enum                 []any
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *bool
+	items                *jsonSchema
+	minItems, maxItems   *int
+	minimum, maximum     *float64
+	exclusiveMin         *float64
+	exclusiveMax         *float64
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+}
+
+var schemaTypeNames = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+var schemaAnnotations = []string{"title", "description", "$schema", "$comment", "default", "examples"}
+
+// compileSchema checks raw, a decoded JSON Schema document, and compiles it. Errors name the
+// offending keyword by its path from the root, e.g. "properties.price.minimum".
+func compileSchema(raw map[string]any) (*jsonSchema, error) {
+	var errs []apperr.FieldError
+	s := compileAt(raw, nil, &errs)
+	if len(errs) > 0 {
+		return nil, apperr.Validation(errs...)
+	}
+	return s, nil
+}
+
+func compileAt(raw map[string]any, path []string, errs *[]apperr.FieldError) *jsonSchema {
+	fail := func(key, format string, args ...any) {
+		*errs = append(*errs, apperr.FieldError{Path: append(slices.Clone(path), key), Message: fmt.Sprintf(format, args...)})
+	}
+	sub := func(key string, v any) *jsonSchema {
+		m, ok := v.(map[string]any)
+		if !ok {
+			fail(key, "must be a schema object")
+			return nil
+		}
+		return compileAt(m, append(slices.Clone(path), key), errs)
+	}
+	count := func(key string, v any) *int {
+		f, ok := v.(float64)
+		if !ok || f < 0 || f != math.Trunc(f) {
+			fail(key, "must be a non-negative integer")
+			return nil
+		}
+		n := int(f)
+		return &n
+	}
+	number := func(key string, v any) *float64 {
+		f, ok := v.(float64)
+		if !ok {
+			fail(key, "must be a number")
+			return nil
+		}
+		return &f
+	}
+
+	s := &jsonSchema{}
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := raw[k]
+		switch k {
+		case "type":
+			switch t := v.(type) {
+			case string:
+				s.types = []string{t}
+			case []any:
+				for _, x := range t {
+					name, _ := x.(string)
+					s.types = append(s.types, name)
+				}
+			}
+			if len(s.types) == 0 {
+				fail(k, "must be a type name or a list of them")
+			}
+			for _, t := range s.types {
+				if !slices.Contains(schemaTypeNames, t) {
+					fail(k, "unknown type %q", t)
+				}
+			}
+		case "enum":
+			list, ok := v.([]any)
+			if !ok || len(list) == 0 {
+				fail(k, "must be a non-empty list")
+				continue
+			}
+			s.enum = list
+		case "const":
+			s.enum = []any{v}
+		case "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				fail(k, "must be an object")
+				continue
+			}
+			s.properties = map[string]*jsonSchema{}
+			for name, p := range props {
+				m, ok := p.(map[string]any)
+				if !ok {
+					*errs = append(*errs, apperr.FieldError{Path: append(slices.Clone(path), k, name), Message: "must be a schema object"})
+					continue
+				}
+				s.properties[name] = compileAt(m, append(slices.Clone(path), k, name), errs)
+			}
+		case "required":
+			list, ok := v.([]any)
+			if !ok {
+				fail(k, "must be a list of property names")
+				continue
+			}
+			for _, x := range list {
+				name, ok := x.(string)
+				if !ok {
+					fail(k, "must be a list of property names")
+					break
+				}
+				s.required = append(s.required, name)
+			}
+		case "additionalProperties":
+			b, ok := v.(bool)
+			if !ok {
+				fail(k, "must be true or false; schemas for additional properties are not supported")
+				continue
+			}
+			s.additionalProperties = &b
+		case "items":
+			s.items = sub(k, v)
+		case "minItems":
+			s.minItems = count(k, v)
+		case "maxItems":
+			s.maxItems = count(k, v)
+		case "minLength":
+			s.minLength = count(k, v)
+		case "maxLength":
+			s.maxLength = count(k, v)
+		case "minimum":
+			s.minimum = number(k, v)
+		case "maximum":
+			s.maximum = number(k, v)
+		case "exclusiveMinimum":
+			s.exclusiveMin = number(k, v)
+		case "exclusiveMaximum":
+			s.exclusiveMax = number(k, v)
+		case "pattern":
+			p, ok := v.(string)
+			if !ok {
+				fail(k, "must be a string")
+				continue
+			}
+			re, err := regexp.Compile(p)
+			if err != nil {
+				fail(k, "is not a valid regular expression: %v", err)
+				continue
+			}
+			s.pattern = re
+		default:
+			if !slices.Contains(schemaAnnotations, k) {
+				fail(k, "is not a supported keyword")
+			}
+		}
+	}
+	return s
+}
+
+// validate checks v, a value decoded by encoding/json, appending one error per violation.
+// Paths start with path and follow object keys and array indexes.
+func (s *jsonSchema) validate(v any, path []string, errs *[]apperr.FieldError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, apperr.FieldError{Path: slices.Clone(path), Message: fmt.Sprintf(format, args...)})
+	}
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return isSchemaType(v, t) }) {
+		fail("must be %s", strings.Join(withArticles(s.types), " or "))
+		return
+	}
+	if s.enum != nil && !slices.ContainsFunc(s.enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		fail("must be one of %s", describeEnum(s.enum))
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				*errs = append(*errs, apperr.FieldError{Path: append(slices.Clone(path), name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(x))
+		for name := range x {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p := append(slices.Clone(path), name)
+			if ps, ok := s.properties[name]; ok {
+				ps.validate(x[name], p, errs)
+			} else if s.additionalProperties != nil && !*s.additionalProperties {
+				*errs = append(*errs, apperr.FieldError{Path: p, Message: "is not allowed"})
+			}
+		}
+	case []any:
+		if s.minItems != nil && len(x) < *s.minItems {
+			fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(x) > *s.maxItems {
+			fail("must have at most %d items", *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range x {
+				s.items.validate(item, append(slices.Clone(path), fmt.Sprint(i)), errs)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(x)
+		if s.minLength != nil && n < *s.minLength {
+			fail("must have at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			fail("must have at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			fail("must match %s", s.pattern)
+		}
+	case float64:
+		if s.minimum != nil && x < *s.minimum {
+			fail("must be at least %v", *s.minimum)
+		}
+		if s.maximum != nil && x > *s.maximum {
+			fail("must be at most %v", *s.maximum)
+		}
+		if s.exclusiveMin != nil && x <= *s.exclusiveMin {
+			fail("must be greater than %v", *s.exclusiveMin)
+		}
+		if s.exclusiveMax != nil && x >= *s.exclusiveMax {
+			fail("must be less than %v", *s.exclusiveMax)
+		}
+	}
+}
+
+func isSchemaType(v any, t string) bool {
+	switch x := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || t == "integer" && x == math.Trunc(x)
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+	return false
+}
+
+func withArticles(types []string) []string {
+	out := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "null":
+			out[i] = "null"
+		case "object", "array", "integer":
+			out[i] = "an " + t
+		default:
+			out[i] = "a " + t
+		}
+	}
+	return out
+}
+
+func describeEnum(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+func decodeJSON[T any](t *testing.T, s string) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decoding %s: %v", s, err)
+	}
+	return v
+}
+
+func fieldErrors(errs []apperr.FieldError) []string {
+	var out []string
+	for _, f := range errs {
+		out = append(out, f.String())
+	}
+	return out
+}
+
+func TestCompileSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{"empty", `{}`, nil},
+		{"annotations", `{"title": "t", "description": "d", "$comment": "c", "default": 1, "examples": [1]}`, nil},
+		{
+			"every keyword",
+			`{"type": ["object", "null"], "required": ["a"], "additionalProperties": false, "properties": {
+				"a": {"type": "array", "minItems": 1, "maxItems": 3, "items": {"enum": ["x", "y"]}},
+				"b": {"type": "string", "minLength": 1, "maxLength": 5, "pattern": "^[a-z]+$"},
+				"c": {"type": "number", "minimum": 0, "maximum": 10, "exclusiveMinimum": 0, "exclusiveMaximum": 10},
+				"d": {"const": true}}}`,
+			nil,
+		},
+		{"unknown keyword", `{"format": "email"}`, []string{"format is not a supported keyword"}},
+		{
+			"nested paths",
+			`{"properties": {"price": {"minimum": "0"}, "tags": {"items": {"oneOf": []}}}}`,
+			[]string{"properties.price.minimum must be a number", "properties.tags.items.oneOf is not a supported keyword"},
+		},
+		{"unknown type", `{"type": "date"}`, []string{`type unknown type "date"`}},
+		{"empty type list", `{"type": []}`, []string{"type must be a type name or a list of them"}},
+		{"empty enum", `{"enum": []}`, []string{"enum must be a non-empty list"}},
+		{"negative count", `{"minLength": -1, "maxItems": 1.5}`, []string{
+			"maxItems must be a non-negative integer", "minLength must be a non-negative integer",
+		}},
+		{"additional properties schema", `{"additionalProperties": {}}`, []string{
+			"additionalProperties must be true or false; schemas for additional properties are not supported",
+		}},
+		{"bad pattern", `{"pattern": "("}`, []string{
+			"pattern is not a valid regular expression: error parsing regexp: missing closing ): `(`",
+		}},
+		{"property not a schema", `{"properties": {"a": 1}}`, []string{"properties.a must be a schema object"}},
+		{"required not names", `{"required": [1]}`, []string{"required must be a list of property names"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := compileSchema(decodeJSON[map[string]any](t, tt.schema))
+			if tt.want == nil {
+				if err != nil || s == nil {
+					t.Fatalf("compileSchema = %v, %v; want a schema", s, err)
+				}
+				return
+			}
+			var ae *apperr.Error
+			if !errors.As(err, &ae) || ae.Code != apperr.CodeValidation {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			if got := fieldErrors(ae.Fields); !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	const schema = `{
+		"type": "object",
+		"required": ["odometer", "inspector"],
+		"additionalProperties": false,
+		"properties": {
+			"odometer": {"type": "integer", "minimum": 0, "exclusiveMaximum": 1000000},
+			"inspector": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^\\p{Lu}+$"},
+			"result": {"enum": ["PASS", "FAIL"]},
+			"score": {"type": "number", "maximum": 10, "exclusiveMinimum": 0},
+			"notes": {"type": ["string", "null"]},
+			"defects": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}}
+		}
+	}`
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"valid", `{"odometer": 1200, "inspector": "ÅB", "result": "PASS", "score": 9.5, "notes": null, "defects": ["dent"]}`, nil},
+		{"not an object", `[]`, []string{"metadata must be an object"}},
+		{"required and additional", `{"color": "red"}`, []string{
+			"metadata.odometer is required", "metadata.inspector is required", "metadata.color is not allowed",
+		}},
+		{"integer", `{"odometer": 1.5, "inspector": "AB"}`, []string{"metadata.odometer must be an integer"}},
+		{"number bounds", `{"odometer": -1, "inspector": "AB", "score": 0}`, []string{
+			"metadata.odometer must be at least 0", "metadata.score must be greater than 0",
+		}},
+		{"exclusive maximum", `{"odometer": 1000000, "inspector": "AB", "score": 11}`, []string{
+			"metadata.odometer must be less than 1e+06", "metadata.score must be at most 10",
+		}},
+		{"string rules", `{"odometer": 1, "inspector": "abcde"}`, []string{
+			"metadata.inspector must have at most 4 characters", `metadata.inspector must match ^\p{Lu}+$`,
+		}},
+		{"too short", `{"odometer": 1, "inspector": "A"}`, []string{"metadata.inspector must have at least 2 characters"}},
+		{"enum", `{"odometer": 1, "inspector": "AB", "result": "MAYBE"}`, []string{"metadata.result must be one of PASS, FAIL"}},
+		{"type list", `{"odometer": 1, "inspector": "AB", "notes": 3}`, []string{"metadata.notes must be a string or null"}},
+		{"array rules", `{"odometer": 1, "inspector": "AB", "defects": ["dent", 2, "scratch"]}`, []string{
+			"metadata.defects must have at most 2 items", "metadata.defects.1 must be a string",
+		}},
+		{"empty array", `{"odometer": 1, "inspector": "AB", "defects": []}`, []string{"metadata.defects must have at least 1 items"}},
+	}
+	s, err := compileSchema(decodeJSON[map[string]any](t, schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []apperr.FieldError
+			s.validate(decodeJSON[any](t, tt.value), []string{"metadata"}, &errs)
+			if got := fieldErrors(errs); !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
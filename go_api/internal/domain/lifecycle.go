@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// Vehicle statuses.
+const (
+	StatusActive       = "ACTIVE"
+	StatusInactive     = "INACTIVE"
+	StatusSold         = "SOLD"
+	StatusDiscontinued = "DISCONTINUED"
+)
+
+// TransitionError reports a movement that the vehicle's current status does not allow.
+type TransitionError struct {
+	Movement string
+	Status   string
+	VIN      string // set when the vehicle is one of a batch
+}
+
+func (e *TransitionError) Error() string {
+	if e.VIN != "" {
+		return fmt.Sprintf("cannot record %s on vehicle %s with status %s", e.Movement, e.VIN, e.Status)
+	}
+	return fmt.Sprintf("cannot record %s on a vehicle with status %s", e.Movement, e.Status)
+}
+
+// RecordMovement checks m against the rules of its movement type, inserts it and moves its vehicle
+// to the type's status and, for types with a location rule, to m.ToLocationID, all in one
+// transaction. The vehicle row is locked while the transition is checked, so concurrent movements
+// serialize.
+// Status and location changes are audited as updates by m.CreatedBy. m.Vehicle is set to the resulting vehicle.
+func (r *Repos) RecordMovement(ctx context.Context, m *Movement) (*Movement, error) {
+	if errs := ValidateMovement(m, time.Now()); len(errs) > 0 {
+		return nil, apperr.Validation(errs...)
+	}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		var v Vehicle
+		if err := tx.DB.Model(&v).Where("id = ?", m.VehicleID).For("UPDATE").Select(); err != nil {
+			return notFound(err, "vehicle with id %d not found", m.VehicleID)
+		}
+		t, err := tx.lockMovementType(ctx, m.Type)
+		if err != nil {
+			return err
+		}
+		if err := validateMetadata(t, m); err != nil {
+			return err
+		}
+		next, err := t.NextStatus(v.Status)
+		if err != nil {
+			return err
+		}
+		before := v
+		v.Status = next
+		m.FromStatus, m.ToStatus = before.Status, next
+		if err := tx.moveVehicle(ctx, t, m, &v); err != nil {
+			return err
+		}
+		if err := tx.saveVehicle(ctx, &before, &v, m.CreatedBy); err != nil {
+			return err
+		}
+		m.Vehicle = &v
+		_, err = tx.CreateMovement(ctx, m)
+		return err
+	})
+	return m, err
+}
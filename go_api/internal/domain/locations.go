@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+)
+
+// Location kinds.
+const (
+	LocationYard   = "YARD"
+	LocationDealer = "DEALER"
+	LocationPlant  = "PLANT"
+)
+
+var LocationKinds = []string{LocationYard, LocationDealer, LocationPlant}
+
+// Location is a place vehicles can be at. Vehicle.CurrentLocationID points at one, and movements
+// whose type has a location rule move vehicles between them.
+type Location struct {
+	tableName struct{}  `pg:"locations"`
+	ID        int64     `pg:"id,pk"`
+	Name      string    `pg:"name,notnull"` // unique, ignoring case
+	Kind      string    `pg:"kind,notnull"`
+	Address   string    `pg:"address,notnull,use_zero"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+	UpdatedAt time.Time `pg:"updated_at,default:now()"`
+}
+
+func validateLocation(l *Location) error {
+	var errs []apperr.FieldError
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" {
+		errs = append(errs, apperr.FieldError{Path: []string{"name"}, Message: "must not be empty"})
+	}
+	if !slices.Contains(LocationKinds, l.Kind) {
+		errs = append(errs, apperr.FieldError{Path: []string{"kind"}, Message: "must be YARD, DEALER or PLANT"})
+	}
+	if len(errs) > 0 {
+		return apperr.Validation(errs...)
+	}
+	return nil
+}
+
+func (r *Repos) CreateLocation(ctx context.Context, l *Location) (*Location, error) {
+	if err := validateLocation(l); err != nil {
+		return nil, err
+	}
+	if _, err := r.DB.ModelContext(ctx, l).Insert(); err != nil {
+		return nil, conflict(err, "a location named %q already exists", l.Name)
+	}
+	return l, nil
+}
+
+func (r *Repos) UpdateLocation(ctx context.Context, l *Location) (*Location, error) {
+	if err := validateLocation(l); err != nil {
+		return nil, err
+	}
+	l.UpdatedAt = time.Now()
+	res, err := r.DB.ModelContext(ctx, l).WherePK().Update()
+	if err != nil {
+		return nil, conflict(err, "a location named %q already exists", l.Name)
+	}
+	if res.RowsAffected() == 0 {
+		return nil, apperr.NotFound("location with id %d not found", l.ID)
+	}
+	return l, nil
+}
+
+// DeleteLocation removes a location that no vehicle or movement refers to. Locations with
+// history stay, so past movements keep resolving.
+func (r *Repos) DeleteLocation(ctx context.Context, id int64) error {
+	res, err := r.DB.ModelContext(ctx, &Location{ID: id}).WherePK().Delete()
+	if err != nil {
+		return inUse(err, "location %d is referenced by vehicles or movements", id)
+	}
+	if res.RowsAffected() == 0 {
+		return apperr.NotFound("location with id %d not found", id)
+	}
+	return nil
+}
+
+func (r *Repos) GetLocationByID(ctx context.Context, id int64) (*Location, error) {
+	var l Location
+	if err := r.DB.ModelContext(ctx, &l).Where("id = ?", id).Select(); err != nil {
+		return nil, notFound(err, "location with id %d not found", id)
+	}
+	return &l, nil
+}
+
+// ListLocations returns locations by name, optionally only those of the given kinds.
+func (r *Repos) ListLocations(ctx context.Context, kinds []string) ([]*Location, error) {
+	var ls []*Location
+	q := r.DB.ModelContext(ctx, &ls)
+	if len(kinds) > 0 {
+		q = q.Where("kind IN (?)", pg.In(kinds))
+	}
+	err := q.Order("name ASC").Select()
+	return ls, err
+}
+
+func (r *Repos) GetLocationsByIDs(ctx context.Context, ids []int64) (map[int64]*Location, error) {
+	var items []*Location
+	if err := r.DB.ModelContext(ctx, &items).Where("id IN (?)", pg.In(ids)).Select(); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]*Location, len(items))
+	for _, l := range items {
+		out[l.ID] = l
+	}
+	return out, nil
+}
+
+// lockLocation checks that location id exists and keeps it from being deleted until the
+// transaction ends. field is the input path reported when it does not exist.
+func (r *Repos) lockLocation(ctx context.Context, id int64, field string) error {
+	var l Location
+	err := r.DB.ModelContext(ctx, &l).Column("id").Where("id = ?", id).For("KEY SHARE").Select()
+	if errors.Is(err, pg.ErrNoRows) {
+		return apperr.Invalid(field, "location %d does not exist", id)
+	}
+	return err
+}
+
+// moveVehicle applies m's location change to v, which must be locked: it records where v was
+// and, when t has a location rule, sets where it is now.
+func (r *Repos) moveVehicle(ctx context.Context, t *MovementType, m *Movement, v *Vehicle) error {
+	m.FromLocationID = v.CurrentLocationID
+	switch {
+	case t.LocationRule == LocationNone && m.ToLocationID != 0:
+		return apperr.Invalid("toLocationId", "is not allowed for %s movements", t.Name)
+	case t.LocationRule == LocationNone:
+		return nil
+	case t.LocationRule == LocationRequired && m.ToLocationID == 0:
+		return apperr.Invalid("toLocationId", "is required for %s movements", t.Name)
+	case t.LocationRule == LocationRequired && m.ToLocationID == v.CurrentLocationID:
+		return apperr.Invalid("toLocationId", "vehicle is already at location %d", m.ToLocationID)
+	}
+	if m.ToLocationID != 0 {
+		if err := r.lockLocation(ctx, m.ToLocationID, "toLocationId"); err != nil {
+			return err
+		}
+	}
+	v.CurrentLocationID = m.ToLocationID
+	return nil
+}
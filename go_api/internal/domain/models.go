@@ -2,7 +2,7 @@ package domain
 
 import "time"
 
-// Permanent roles seeded in DB.
+// Permanent roles seeded in DB. Admins may add custom roles alongside them.
 const (
 	RoleAdmin  = "Admin"
 	RoleEditor = "Editor"
@@ -10,10 +10,25 @@ const (
 )
 
 type Role struct {
-	tableName struct{}  `pg:"roles"`
-	ID        int64     `pg:"id,pk"`
-	Name      string    `pg:"name,unique,notnull"`
-	CreatedAt time.Time `pg:"created_at,default:now()"`
+	tableName   struct{}      `pg:"roles"`
+	ID          int64         `pg:"id,pk"`
+	Name        string        `pg:"name,unique,notnull"`
+	CreatedAt   time.Time     `pg:"created_at,default:now()"`
+	Permissions []*Permission `pg:"many2many:role_permissions"`
+}
+
+type Permission struct {
+	tableName   struct{} `pg:"permissions"`
+	ID          int64    `pg:"id,pk"`
+	Name        string   `pg:"name,unique,notnull"`
+	Description string   `pg:"description,use_zero"`
+}
+
+// RolePermission is the join row behind Role.Permissions.
+type RolePermission struct {
+	tableName    struct{} `pg:"role_permissions"`
+	RoleID       int64    `pg:"role_id,pk"`
+	PermissionID int64    `pg:"permission_id,pk"`
 }
 
 type User struct {
@@ -28,22 +43,26 @@ type User struct {
 
 // Vehicle basics (invented but realistic for CRUD)
 type Vehicle struct {
-	tableName    struct{}  `pg:"vehicles"`
-	ID           int64     `pg:"id,pk"`
-	VIN          string    `pg:"vin,unique,notnull"`
-	Name         string    `pg:"name,notnull"`
-	ModelCode    string    `pg:"model_code,notnull"`    // e.g., "F-150"
-	TractionType string    `pg:"traction_type,notnull"` // RWD | FWD | AWD | 4WD
-	ReleaseYear  int       `pg:"release_year,notnull"`
-	BatchNumber  string    `pg:"batch_number,notnull"`
-	Color        string    `pg:"color"`
-	Mileage      int       `pg:"mileage,default:0"`
-	Status       string    `pg:"status,notnull,default:'ACTIVE'"` // ACTIVE | INACTIVE | DISCONTINUED
-	CreatedAt    time.Time `pg:"created_at,default:now()"`
-	UpdatedAt    time.Time `pg:"updated_at,default:now()"`
+	tableName         struct{}   `pg:"vehicles,discard_unknown_columns"`
+	ID                int64      `pg:"id,pk"`
+	VIN               string     `pg:"vin,unique,notnull"`
+	Name              string     `pg:"name,notnull"`
+	ModelCode         string     `pg:"model_code,notnull"`    // e.g., "F-150"
+	TractionType      string     `pg:"traction_type,notnull"` // RWD | FWD | AWD | 4WD
+	ReleaseYear       int        `pg:"release_year,notnull"`
+	BatchNumber       string     `pg:"batch_number,notnull"`
+	Manufacturer      string     `pg:"manufacturer,notnull,use_zero"` // decoded from the VIN unless given
+	PlantCode         string     `pg:"plant_code,notnull,use_zero"`   // VIN position 11 unless given
+	Color             string     `pg:"color"`
+	Mileage           int        `pg:"mileage,default:0"`
+	Status            string     `pg:"status,notnull,default:'ACTIVE'"` // ACTIVE | INACTIVE | SOLD | DISCONTINUED
+	CurrentLocationID int64      `pg:"current_location_id"`             // 0 (NULL) when unknown; see MovementType.LocationRule
+	CreatedAt         time.Time  `pg:"created_at,default:now()"`
+	UpdatedAt         time.Time  `pg:"updated_at,default:now()"`
+	DeletedAt         *time.Time `pg:"deleted_at,soft_delete"` // set by DeleteVehicle; queries skip these rows unless asked
 }
 
-// Movement types (inventory lifecycle events)
+// Built-in movement types (inventory lifecycle events). Admins may add others; see MovementType.
 const (
 	MoveSale         = "SALE"
 	MoveDefect       = "DEFECT"
@@ -53,14 +72,37 @@ const (
 )
 
 type Movement struct {
-	tableName   struct{}       `pg:"movements"`
-	ID          int64          `pg:"id,pk"`
-	VehicleID   int64          `pg:"vehicle_id,notnull"`
-	Vehicle     *Vehicle       `pg:"rel:has-one,fk:vehicle_id"`
-	Type        string         `pg:"type,notnull"` // enum-ish
-	Description string         `pg:"description"`
-	OccurredAt  time.Time      `pg:"occurred_at,notnull"`
-	Metadata    map[string]any `pg:"metadata,type:jsonb"`
-	CreatedBy   int64          `pg:"created_by,notnull"`
-	CreatedAt   time.Time      `pg:"created_at,default:now()"`
+	tableName      struct{}       `pg:"movements"`
+	ID             int64          `pg:"id,pk"`
+	VehicleID      int64          `pg:"vehicle_id,notnull"`
+	Vehicle        *Vehicle       `pg:"rel:has-one,fk:vehicle_id"`
+	Type           string         `pg:"type,notnull"` // a MovementType name
+	Description    string         `pg:"description"`
+	OccurredAt     time.Time      `pg:"occurred_at,notnull"`
+	Metadata       map[string]any `pg:"metadata,type:jsonb"`
+	FromLocationID int64          `pg:"from_location_id"` // where the vehicle was when the movement was recorded
+	ToLocationID   int64          `pg:"to_location_id"`   // set by types with a location rule
+	FromStatus     string         `pg:"from_status"`      // vehicle status before and after; "" for movements
+	ToStatus       string         `pg:"to_status"`        // recorded before corrections were supported
+	ReversesID     int64          `pg:"reverses_id"`      // the movement this one compensates; see ReverseMovement
+	VoidedAt       *time.Time     `pg:"voided_at"`
+	VoidedBy       int64          `pg:"voided_by"`
+	VoidReason     string         `pg:"void_reason"`
+	CorrelationID  string         `pg:"correlation_id,type:uuid"` // shared by movements recorded by ApplyMovementToBatch
+	CreatedBy      int64          `pg:"created_by,notnull"`
+	CreatedAt      time.Time      `pg:"created_at,default:now()"`
+	UpdatedAt      time.Time      `pg:"updated_at,default:now()"`
+}
+
+// Session is one signed-in device. Access tokens carry its ID and stop working once it is revoked;
+// the refresh token is stored only as a hash and rotated on every use.
+type Session struct {
+	tableName   struct{}   `pg:"sessions"`
+	ID          int64      `pg:"id,pk"`
+	UserID      int64      `pg:"user_id,notnull"`
+	RefreshHash string     `pg:"refresh_hash,unique,notnull"`
+	ExpiresAt   time.Time  `pg:"expires_at,notnull"`
+	RevokedAt   *time.Time `pg:"revoked_at"`
+	CreatedAt   time.Time  `pg:"created_at,default:now()"`
+	RotatedAt   *time.Time `pg:"rotated_at"`
 }
@@ -0,0 +1,374 @@
+package domain
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+)
+
+// Correction actions recorded for movements.
+const (
+	CorrectionUpdate  = "UPDATE"
+	CorrectionVoid    = "VOID"
+	CorrectionReverse = "REVERSE"
+)
+
+// defaultMovementEditWindow applies when Repos.MovementEditWindow is not set.
+const defaultMovementEditWindow = 24 * time.Hour
+
+// MovementAudit is one correction of a movement, holding the values it replaced.
+type MovementAudit struct {
+	tableName  struct{}               `pg:"movement_audit"`
+	ID         int64                  `pg:"id,pk"`
+	MovementID int64                  `pg:"movement_id,notnull"`
+	Action     string                 `pg:"action,notnull"`
+	ActorID    int64                  `pg:"actor_id"`
+	Changes    map[string]FieldChange `pg:"changes,type:jsonb"`
+	Reason     string                 `pg:"reason"`
+	CreatedAt  time.Time              `pg:"created_at,default:now()"`
+}
+
+// MovementChanges lists the fields UpdateMovement sets. Nil fields are left as they are.
+type MovementChanges struct {
+	Type        *string
+	Description *string
+	OccurredAt  *time.Time
+	Metadata    map[string]any
+}
+
+// movementFields lists the correctable fields of m, keyed by their GraphQL names.
+func movementFields(m *Movement) map[string]any {
+	return map[string]any{
+		"type": m.Type, "description": m.Description, "occurredAt": m.OccurredAt.UTC(),
+		"metadata": m.Metadata, "toStatus": m.ToStatus,
+	}
+}
+
+func diffMovements(before, after *Movement) map[string]FieldChange {
+	old, cur := movementFields(before), movementFields(after)
+	changes := map[string]FieldChange{}
+	for k := range old {
+		if !reflect.DeepEqual(old[k], cur[k]) {
+			changes[k] = FieldChange{Old: old[k], New: cur[k]}
+		}
+	}
+	return changes
+}
+
+func (r *Repos) recordMovementAudit(ctx context.Context, a *MovementAudit) error {
+	_, err := r.DB.ModelContext(ctx, a).Insert()
+	return err
+}
+
+// lockCorrection locks the vehicle of movement id, then the movement, in the order RecordMovement
+// locks vehicles, and rejects movements that are void or are reversals.
+func (r *Repos) lockCorrection(ctx context.Context, id int64) (*Movement, *Vehicle, error) {
+	var vehicleID int64
+	if err := r.DB.ModelContext(ctx, (*Movement)(nil)).Column("vehicle_id").Where("id = ?", id).Select(&vehicleID); err != nil {
+		return nil, nil, notFound(err, "movement with id %d not found", id)
+	}
+	var v Vehicle
+	if err := r.DB.ModelContext(ctx, &v).Where("id = ?", vehicleID).For("UPDATE").Select(); err != nil {
+		return nil, nil, notFound(err, "vehicle with id %d not found", vehicleID)
+	}
+	var m Movement
+	if err := r.DB.ModelContext(ctx, &m).Where("id = ?", id).For("UPDATE").Select(); err != nil {
+		return nil, nil, notFound(err, "movement with id %d not found", id)
+	}
+	if m.VoidedAt != nil {
+		return nil, nil, apperr.Conflict("movement %d is void", id)
+	}
+	if m.ReversesID != 0 {
+		return nil, nil, apperr.Conflict("movement %d reverses movement %d and cannot be corrected", id, m.ReversesID)
+	}
+	return &m, &v, nil
+}
+
+func (r *Repos) isReversed(ctx context.Context, id int64) (bool, error) {
+	return r.DB.ModelContext(ctx, (*Movement)(nil)).Where("reverses_id = ?", id).Exists()
+}
+
+// UpdateMovement applies c to movement id, keeping the replaced values in its history. Only
+// movements recorded within MovementEditWindow may be edited unless late is set.
+//
+// A new type must be recordable from the status the vehicle had before the movement, and must
+// agree with the old one on whether the movement relocates the vehicle. If it leaves the vehicle
+// in a different status, the movement must be the vehicle's latest and the vehicle is moved to
+// that status. Movements recorded before status tracking cannot change type.
+func (r *Repos) UpdateMovement(ctx context.Context, id int64, c MovementChanges, actorID int64, late bool) (*Movement, error) {
+	var m *Movement
+	err := r.InTx(ctx, func(tx *Repos) error {
+		var (
+			v   *Vehicle
+			err error
+		)
+		m, v, err = tx.lockCorrection(ctx, id)
+		if err != nil {
+			return err
+		}
+		window := tx.MovementEditWindow
+		if window == 0 {
+			window = defaultMovementEditWindow
+		}
+		if !late && time.Since(m.CreatedAt) > window {
+			return apperr.Forbidden("movement %d was recorded more than %s ago and can no longer be edited", id, window)
+		}
+		before := *m
+		if c.Type != nil {
+			m.Type = *c.Type
+		}
+		if c.Description != nil {
+			m.Description = *c.Description
+		}
+		if c.OccurredAt != nil {
+			m.OccurredAt = *c.OccurredAt
+		}
+		if c.Metadata != nil {
+			m.Metadata = c.Metadata
+		}
+		if errs := ValidateMovement(m, time.Now()); len(errs) > 0 {
+			return apperr.Validation(errs...)
+		}
+		t, err := tx.lockMovementType(ctx, m.Type)
+		if err != nil {
+			return err
+		}
+		if m.Type != before.Type {
+			if err := tx.retype(ctx, &before, m, t, v, actorID); err != nil {
+				return err
+			}
+		}
+		if err := validateMetadata(t, m); err != nil {
+			return err
+		}
+		changes := diffMovements(&before, m)
+		if len(changes) == 0 {
+			return nil
+		}
+		m.UpdatedAt = time.Now()
+		if _, err := tx.DB.ModelContext(ctx, m).
+			Column("type", "description", "occurred_at", "metadata", "to_status", "updated_at").
+			WherePK().Update(); err != nil {
+			return err
+		}
+		if err := tx.recordMovementAudit(ctx, &MovementAudit{
+			MovementID: id, Action: CorrectionUpdate, ActorID: actorID, Changes: changes,
+		}); err != nil {
+			return err
+		}
+		m.Vehicle = v
+		payload := movementPayload(m)
+		payload["changes"] = changes
+		return tx.recordEvent(ctx, &DomainEvent{
+			Type: EventMovementUpdated, AggregateType: AggregateMovement, AggregateID: id,
+			ActorID: actorID, Payload: payload,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// retype checks that m, recorded as before.Type, may become t, and moves v to the status t
+// leaves it in.
+func (r *Repos) retype(ctx context.Context, before, m *Movement, t *MovementType, v *Vehicle, actorID int64) error {
+	if before.FromStatus == "" {
+		return apperr.Invalid("type", "movement %d was recorded before status tracking; void it and record a new one instead", m.ID)
+	}
+	reversed, err := r.isReversed(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if reversed {
+		return apperr.Invalid("type", "movement %d has been reversed; its type can no longer change", m.ID)
+	}
+	old, err := r.GetMovementType(ctx, before.Type)
+	if err != nil {
+		return err
+	}
+	if (old.LocationRule == LocationNone) != (t.LocationRule == LocationNone) {
+		return apperr.Invalid("type", "%s and %s differ in whether they relocate the vehicle", old.Name, t.Name)
+	}
+	if t.LocationRule == LocationRequired && m.ToLocationID == 0 {
+		return apperr.Invalid("type", "%s movements need a destination, which movement %d lacks", t.Name, m.ID)
+	}
+	next, err := t.NextStatus(m.FromStatus)
+	if err != nil {
+		return apperr.Invalid("type", "%s", err.Error())
+	}
+	m.ToStatus = next
+	if next == before.ToStatus {
+		return nil
+	}
+	later, err := r.DB.ModelContext(ctx, (*Movement)(nil)).
+		Where("vehicle_id = ? AND id > ? AND voided_at IS NULL", m.VehicleID, m.ID).Exists()
+	if err != nil {
+		return err
+	}
+	if later || v.Status != before.ToStatus {
+		return apperr.Invalid("type", "%s would change the vehicle's status, which later changes depend on", t.Name)
+	}
+	prev := *v
+	v.Status = next
+	return r.saveVehicle(ctx, &prev, v, actorID)
+}
+
+// VoidMovement marks movement id void, so reports no longer count it, and records why. The
+// vehicle is left as it is; use ReverseMovement to undo a movement's effect. Reversed movements
+// and reversals cannot be voided.
+func (r *Repos) VoidMovement(ctx context.Context, id int64, reason string, actorID int64) (*Movement, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason", "must not be empty")
+	}
+	var m *Movement
+	err := r.InTx(ctx, func(tx *Repos) error {
+		var (
+			v   *Vehicle
+			err error
+		)
+		m, v, err = tx.lockCorrection(ctx, id)
+		if err != nil {
+			return err
+		}
+		reversed, err := tx.isReversed(ctx, id)
+		if err != nil {
+			return err
+		}
+		if reversed {
+			return apperr.Conflict("movement %d has been reversed", id)
+		}
+		now := time.Now()
+		m.VoidedAt, m.VoidedBy, m.VoidReason, m.UpdatedAt = &now, actorID, reason, now
+		if _, err := tx.DB.ModelContext(ctx, m).
+			Column("voided_at", "voided_by", "void_reason", "updated_at").
+			WherePK().Update(); err != nil {
+			return err
+		}
+		if err := tx.recordMovementAudit(ctx, &MovementAudit{
+			MovementID: id, Action: CorrectionVoid, ActorID: actorID, Reason: reason,
+			Changes: map[string]FieldChange{"voidedAt": {Old: nil, New: now}},
+		}); err != nil {
+			return err
+		}
+		m.Vehicle = v
+		payload := movementPayload(m)
+		payload["reason"] = reason
+		return tx.recordEvent(ctx, &DomainEvent{
+			Type: EventMovementVoided, AggregateType: AggregateMovement, AggregateID: id,
+			ActorID: actorID, Payload: payload,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReverseMovement records a compensating movement that returns the vehicle to the status, and
+// for relocating types the location, it had before movement id, and returns it. The vehicle must
+// still be where the movement left it. The reversal has the original's type and skips its
+// rules; reports count neither.
+func (r *Repos) ReverseMovement(ctx context.Context, id int64, reason string, actorID int64) (*Movement, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason", "must not be empty")
+	}
+	var rev *Movement
+	err := r.InTx(ctx, func(tx *Repos) error {
+		m, v, err := tx.lockCorrection(ctx, id)
+		if err != nil {
+			return err
+		}
+		if m.FromStatus == "" {
+			return apperr.Conflict("movement %d was recorded before status tracking and cannot be reversed", id)
+		}
+		reversed, err := tx.isReversed(ctx, id)
+		if err != nil {
+			return err
+		}
+		if reversed {
+			return apperr.Conflict("movement %d has already been reversed", id)
+		}
+		t, err := tx.lockMovementType(ctx, m.Type)
+		if err != nil {
+			return err
+		}
+		relocated := t.LocationRule != LocationNone
+		if v.Status != m.ToStatus || (relocated && v.CurrentLocationID != m.ToLocationID) {
+			return apperr.Conflict("vehicle %d has changed since movement %d; reverse the later movements first", v.ID, id)
+		}
+		now := time.Now()
+		rev = &Movement{
+			VehicleID: v.ID, Type: m.Type, Description: reason, OccurredAt: now,
+			FromLocationID: v.CurrentLocationID, FromStatus: v.Status, ToStatus: m.FromStatus,
+			ReversesID: id, CreatedBy: actorID, CreatedAt: now,
+		}
+		before := *v
+		v.Status = m.FromStatus
+		if relocated {
+			rev.ToLocationID = m.FromLocationID
+			v.CurrentLocationID = m.FromLocationID
+		}
+		if err := tx.saveVehicle(ctx, &before, v, actorID); err != nil {
+			return err
+		}
+		rev.Vehicle = v
+		if _, err := tx.CreateMovement(ctx, rev); err != nil {
+			return err
+		}
+		if err := tx.recordMovementAudit(ctx, &MovementAudit{
+			MovementID: id, Action: CorrectionReverse, ActorID: actorID, Reason: reason,
+			Changes: map[string]FieldChange{"reversedById": {Old: nil, New: rev.ID}},
+		}); err != nil {
+			return err
+		}
+		payload := movementPayload(m)
+		payload["reversal"] = movementPayload(rev)["movement"]
+		return tx.recordEvent(ctx, &DomainEvent{
+			Type: EventMovementReversed, AggregateType: AggregateMovement, AggregateID: id,
+			ActorID: actorID, Payload: payload,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// ListMovementAudit returns the corrections of a movement, newest first.
+func (r *Repos) ListMovementAudit(ctx context.Context, movementID int64) ([]*MovementAudit, error) {
+	var items []*MovementAudit
+	err := r.DB.ModelContext(ctx, &items).Where("movement_id = ?", movementID).
+		Order("created_at DESC", "id DESC").Select()
+	return items, err
+}
+
+// GetReversalsOf maps each of ids to the ID of the movement reversing it, or 0.
+func (r *Repos) GetReversalsOf(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	var items []*Movement
+	if err := r.DB.ModelContext(ctx, &items).Column("id", "reverses_id").
+		Where("reverses_id IN (?)", pg.In(ids)).Select(); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]int64, len(ids))
+	for _, id := range ids {
+		out[id] = 0
+	}
+	for _, m := range items {
+		out[m.ReversesID] = m.ID
+	}
+	return out, nil
+}
+
+// counted is the condition, on the movements row aliased alias, under which reports count it:
+// it is not void, not a reversal and not reversed.
+func counted(alias string) string {
+	return alias + ".voided_at IS NULL AND " + alias + ".reverses_id IS NULL AND NOT EXISTS (" +
+		"SELECT 1 FROM movements rev WHERE rev.reverses_id = " + alias + ".id)"
+}
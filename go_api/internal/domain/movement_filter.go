@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// MovementFilter narrows a movement query. Nil or empty fields are ignored; all set fields must match.
+// Like VehicleFilter, its Where method plugs into any movements query via (*orm.Query).Apply.
+type MovementFilter struct {
+	VehicleID     *int64
+	Types         []string
+	From          *time.Time // inclusive, on occurred_at
+	To            *time.Time // exclusive, on occurred_at
+	CorrelationID *string    // movements recorded together by ApplyMovementToBatch
+	// IncludeDeleted also matches movements of soft-deleted vehicles.
+	IncludeDeleted bool
+}
+
+func (f MovementFilter) Where(q *orm.Query) (*orm.Query, error) {
+	if f.VehicleID != nil {
+		q = q.Where("?TableAlias.vehicle_id = ?", *f.VehicleID)
+	}
+	if len(f.Types) > 0 {
+		q = q.Where("?TableAlias.type IN (?)", pg.In(f.Types))
+	}
+	if f.From != nil {
+		q = q.Where("?TableAlias.occurred_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("?TableAlias.occurred_at < ?", *f.To)
+	}
+	if f.CorrelationID != nil {
+		q = q.Where("?TableAlias.correlation_id = ?", *f.CorrelationID)
+	}
+	if !f.IncludeDeleted {
+		q = q.Where("EXISTS (SELECT 1 FROM vehicles v WHERE v.id = ?TableAlias.vehicle_id AND v.deleted_at IS NULL)")
+	}
+	return q, nil
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// SetMovementSchema replaces the JSON Schema that metadata of movements of a type must satisfy.
+// It applies to movements recorded from then on; stored metadata is not revalidated. Errors in
+// the schema have paths relative to its root.
+func (r *Repos) SetMovementSchema(ctx context.Context, name string, schema map[string]any, actorID int64) (*MovementType, error) {
+	if _, err := compileSchema(schema); err != nil {
+		return nil, err
+	}
+	t := MovementType{Name: name, MetadataSchema: schema, UpdatedBy: actorID, UpdatedAt: time.Now()}
+	res, err := r.DB.ModelContext(ctx, &t).
+		Column("metadata_schema", "updated_by", "updated_at").
+		WherePK().Update()
+	if err != nil {
+		return nil, err
+	}
+	if res.RowsAffected() == 0 {
+		return nil, apperr.Invalid("type", "unknown movement type %s", name)
+	}
+	return r.GetMovementType(ctx, name)
+}
+
+// DeleteMovementSchema lets a movement type accept any metadata again.
+func (r *Repos) DeleteMovementSchema(ctx context.Context, name string, actorID int64) error {
+	res, err := r.DB.ModelContext(ctx, (*MovementType)(nil)).
+		Set("metadata_schema = NULL, updated_by = ?, updated_at = now()", actorID).
+		Where("name = ?", name).
+		Where("metadata_schema IS NOT NULL").
+		Update()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return apperr.NotFound("no metadata schema for %s movements", name)
+	}
+	return nil
+}
+
+// ListMovementSchemas returns the movement types that have a metadata schema, by name.
+func (r *Repos) ListMovementSchemas(ctx context.Context) ([]*MovementType, error) {
+	var ts []*MovementType
+	err := r.DB.ModelContext(ctx, &ts).Where("metadata_schema IS NOT NULL").Order("name ASC").Select()
+	return ts, err
+}
+
+// validateMetadata checks m.Metadata against the schema of t, if it has one. Missing metadata is
+// checked as an empty object. Error paths start with "metadata".
+func validateMetadata(t *MovementType, m *Movement) error {
+	if t.MetadataSchema == nil {
+		return nil
+	}
+	schema, err := metadataSchema(t)
+	if err != nil {
+		return err
+	}
+	var doc any = map[string]any{}
+	if m.Metadata != nil {
+		doc = m.Metadata
+	}
+	var errs []apperr.FieldError
+	schema.validate(doc, []string{"metadata"}, &errs)
+	if len(errs) > 0 {
+		return apperr.Validation(errs...)
+	}
+	return nil
+}
+
+// compiledSchemas caches compiled metadata schemas by movement type name, so a batch of movements
+// compiles its type's schema once. An entry is reused while the type's UpdatedAt is unchanged;
+// every write to a movement type bumps it.
+var compiledSchemas sync.Map // string -> compiledSchema
+
+type compiledSchema struct {
+	updatedAt time.Time
+	schema    *jsonSchema
+}
+
+func metadataSchema(t *MovementType) (*jsonSchema, error) {
+	if c, ok := compiledSchemas.Load(t.Name); ok && c.(compiledSchema).updatedAt.Equal(t.UpdatedAt) {
+		return c.(compiledSchema).schema, nil
+	}
+	schema, err := compileSchema(t.MetadataSchema)
+	if err != nil {
+		return nil, err
+	}
+	compiledSchemas.Store(t.Name, compiledSchema{updatedAt: t.UpdatedAt, schema: schema})
+	return schema, nil
+}
+
+// Typed views of well-known metadata keys. Fields are nil when the key is missing or holds a
+// value of another type, so free-form metadata recorded before schemas existed stays readable.
+type (
+	SaleDetails struct {
+		Price    *float64
+		Currency *string
+		Buyer    *string
+	}
+	DefectDetails struct {
+		Code     *string
+		Severity *string
+	}
+	TransferDetails struct {
+		From *string
+		To   *string
+	}
+)
+
+// Details returns the typed view of m's metadata for SALE, DEFECT and TRANSFER movements, and
+// nil for other types.
+func (m *Movement) Details() any {
+	str := func(key string) *string {
+		if s, ok := m.Metadata[key].(string); ok {
+			return &s
+		}
+		return nil
+	}
+	switch m.Type {
+	case MoveSale:
+		d := SaleDetails{Currency: str("currency"), Buyer: str("buyer")}
+		if p, ok := m.Metadata["price"].(float64); ok {
+			d.Price = &p
+		}
+		return &d
+	case MoveDefect:
+		return &DefectDetails{Code: str("code"), Severity: str("severity")}
+	case MoveTransfer:
+		return &TransferDetails{From: str("from"), To: str("to")}
+	}
+	return nil
+}
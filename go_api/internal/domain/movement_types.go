@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+)
+
+// Location rules: whether a movement of a type names the location it takes the vehicle to.
+const (
+	LocationNone     = "NONE"     // toLocationId is not allowed; the vehicle stays where it is
+	LocationOptional = "OPTIONAL" // the vehicle moves to toLocationId, or to no location without one
+	LocationRequired = "REQUIRED"
+)
+
+var locationRules = []string{LocationNone, LocationOptional, LocationRequired}
+
+// MovementType is a kind of movement and the rules for recording it. The built-in types (the
+// Move* constants) are seeded by migration and cannot be deleted; Admins may add others.
+type MovementType struct {
+	tableName      struct{}       `pg:"movement_types"`
+	Name           string         `pg:"name,pk"`
+	Description    string         `pg:"description,notnull,use_zero"`
+	FromStatuses   []string       `pg:"from_statuses,array,notnull"` // statuses the movement may be recorded from
+	ToStatus       string         `pg:"to_status"`                   // status it leaves the vehicle in; "" (NULL) keeps it
+	LocationRule   string         `pg:"location_rule,notnull"`
+	MetadataSchema map[string]any `pg:"metadata_schema,type:jsonb"` // see jsonSchema; nil accepts any metadata
+	BuiltIn        bool           `pg:"built_in,notnull,use_zero"`
+	UpdatedBy      int64          `pg:"updated_by"`
+	CreatedAt      time.Time      `pg:"created_at,default:now()"`
+	UpdatedAt      time.Time      `pg:"updated_at,default:now()"`
+}
+
+// NextStatus returns the status a vehicle in status ends up in after a movement of this type.
+func (t *MovementType) NextStatus(status string) (string, error) {
+	if !slices.Contains(t.FromStatuses, status) {
+		return "", &TransitionError{Movement: t.Name, Status: status}
+	}
+	if t.ToStatus == "" {
+		return status, nil
+	}
+	return t.ToStatus, nil
+}
+
+var movementTypeName = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,39}$`)
+
+func validateMovementType(t *MovementType) error {
+	var errs []apperr.FieldError
+	fail := func(path, format string, args ...any) {
+		errs = append(errs, apperr.FieldError{Path: []string{path}, Message: fmt.Sprintf(format, args...)})
+	}
+	t.Description = strings.TrimSpace(t.Description)
+	if len(t.FromStatuses) == 0 {
+		fail("fromStatuses", "must not be empty")
+	}
+	for _, s := range t.FromStatuses {
+		if !slices.Contains(VehicleStatuses, s) {
+			fail("fromStatuses", "unknown status %s", s)
+		}
+	}
+	if t.ToStatus != "" && !slices.Contains(VehicleStatuses, t.ToStatus) {
+		fail("toStatus", "unknown status %s", t.ToStatus)
+	}
+	if !slices.Contains(locationRules, t.LocationRule) {
+		fail("locationRule", "must be NONE, OPTIONAL or REQUIRED")
+	}
+	if len(errs) > 0 {
+		return apperr.Validation(errs...)
+	}
+	return nil
+}
+
+// CreateMovementType adds a custom movement type. It has no metadata schema until one is set.
+func (r *Repos) CreateMovementType(ctx context.Context, t *MovementType) (*MovementType, error) {
+	t.BuiltIn, t.MetadataSchema = false, nil
+	if !movementTypeName.MatchString(t.Name) {
+		return nil, apperr.Invalid("name", "must be 1 to 40 upper-case letters, digits or underscores, starting with a letter")
+	}
+	if slices.Contains(correctionEvents, movementEvent(t.Name)) {
+		return nil, apperr.Invalid("name", "%s is reserved: %s is a movement correction event", t.Name, movementEvent(t.Name))
+	}
+	if err := validateMovementType(t); err != nil {
+		return nil, err
+	}
+	if _, err := r.DB.ModelContext(ctx, t).Insert(); err != nil {
+		return nil, conflict(err, "movement type %s already exists", t.Name)
+	}
+	return t, nil
+}
+
+// UpdateMovementType saves the description and rules of t. Names are permanent, and the metadata
+// schema is changed only through SetMovementSchema. Recorded movements are not rechecked.
+// Built-in types keep their resulting status and location rule, which reports, fleet stats and
+// batches rely on; only the statuses they may be recorded from can change.
+func (r *Repos) UpdateMovementType(ctx context.Context, t *MovementType) (*MovementType, error) {
+	if err := validateMovementType(t); err != nil {
+		return nil, err
+	}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		cur := MovementType{Name: t.Name}
+		if err := tx.DB.ModelContext(ctx, &cur).WherePK().For("UPDATE").Select(); err != nil {
+			return notFound(err, "movement type %s not found", t.Name)
+		}
+		if cur.BuiltIn {
+			var errs []apperr.FieldError
+			if t.ToStatus != cur.ToStatus {
+				errs = append(errs, apperr.FieldError{Path: []string{"toStatus"}, Message: "cannot change on built-in type " + t.Name})
+			}
+			if t.LocationRule != cur.LocationRule {
+				errs = append(errs, apperr.FieldError{Path: []string{"locationRule"}, Message: "cannot change on built-in type " + t.Name})
+			}
+			if len(errs) > 0 {
+				return apperr.Validation(errs...)
+			}
+		}
+		t.UpdatedAt = time.Now()
+		_, err := tx.DB.ModelContext(ctx, t).
+			Column("description", "from_statuses", "to_status", "location_rule", "updated_by", "updated_at").
+			WherePK().Update()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetMovementType(ctx, t.Name)
+}
+
+// DeleteMovementType removes a custom movement type that no movement uses.
+func (r *Repos) DeleteMovementType(ctx context.Context, name string) error {
+	t, err := r.GetMovementType(ctx, name)
+	if err != nil {
+		return err
+	}
+	if t.BuiltIn {
+		return apperr.Forbidden("built-in movement type %s cannot be deleted", name)
+	}
+	if _, err := r.DB.ModelContext(ctx, t).WherePK().Delete(); err != nil {
+		return inUse(err, "movement type %s is used by recorded movements", name)
+	}
+	return nil
+}
+
+func (r *Repos) GetMovementType(ctx context.Context, name string) (*MovementType, error) {
+	t := MovementType{Name: name}
+	if err := r.DB.ModelContext(ctx, &t).WherePK().Select(); err != nil {
+		return nil, notFound(err, "movement type %s not found", name)
+	}
+	return &t, nil
+}
+
+// ListMovementTypes returns the built-in types, then the custom ones, each by name.
+func (r *Repos) ListMovementTypes(ctx context.Context) ([]*MovementType, error) {
+	var ts []*MovementType
+	err := r.DB.ModelContext(ctx, &ts).Order("built_in DESC", "name ASC").Select()
+	return ts, err
+}
+
+// lockMovementType loads the type a movement is being recorded with and keeps it from being
+// deleted until the transaction ends.
+func (r *Repos) lockMovementType(ctx context.Context, name string) (*MovementType, error) {
+	t := MovementType{Name: name}
+	err := r.DB.ModelContext(ctx, &t).WherePK().For("KEY SHARE").Select()
+	if errors.Is(err, pg.ErrNoRows) {
+		return nil, apperr.Invalid("type", "unknown movement type %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
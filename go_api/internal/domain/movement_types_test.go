@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+func TestNextStatus(t *testing.T) {
+	sell := &MovementType{Name: "SALE", FromStatuses: []string{StatusActive, StatusInactive}, ToStatus: StatusSold}
+	inspect := &MovementType{Name: "INSPECTION", FromStatuses: []string{StatusActive, StatusInactive}}
+	tests := []struct {
+		name    string
+		t       *MovementType
+		status  string
+		want    string
+		wantErr bool
+	}{
+		{"moves to the type's status", sell, StatusActive, StatusSold, false},
+		{"from any allowed status", sell, StatusInactive, StatusSold, false},
+		{"keeps the status without one", inspect, StatusInactive, StatusInactive, false},
+		{"rejects other statuses", sell, StatusSold, "", true},
+		{"rejects other statuses without a target", inspect, StatusDiscontinued, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.t.NextStatus(tt.status)
+			if tt.wantErr {
+				var te *TransitionError
+				if !errors.As(err, &te) || te.Movement != tt.t.Name || te.Status != tt.status {
+					t.Fatalf("err = %v, want a TransitionError for %s from %s", err, tt.t.Name, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NextStatus(%s) = %s, want %s", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMovementType(t *testing.T) {
+	tests := []struct {
+		name       string
+		t          MovementType
+		wantFields []string
+	}{
+		{"valid", MovementType{FromStatuses: []string{StatusActive}, ToStatus: StatusSold, LocationRule: LocationNone}, nil},
+		{"no target status", MovementType{FromStatuses: []string{StatusActive}, LocationRule: LocationRequired}, nil},
+		{"no from statuses", MovementType{LocationRule: LocationNone}, []string{"fromStatuses"}},
+		{
+			"unknown statuses and rule",
+			MovementType{FromStatuses: []string{"PARKED"}, ToStatus: "GONE", LocationRule: "SOMEWHERE"},
+			[]string{"fromStatuses", "toStatus", "locationRule"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMovementType(&tt.t)
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var ae *apperr.Error
+			if !errors.As(err, &ae) || ae.Code != apperr.CodeValidation {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			var got []string
+			for _, f := range ae.Fields {
+				got = append(got, f.Path[0])
+			}
+			if !slices.Equal(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/go-pg/pg/v10"
+)
+
+// Channels announced by the triggers of migration 0010.
+const (
+	ChannelMovementCreated = "movement_created"
+	ChannelVehicleChanged  = "vehicle_changed"
+)
+
+// MovementNotice is the payload of ChannelMovementCreated.
+type MovementNotice struct {
+	ID        int64 `json:"id"`
+	VehicleID int64 `json:"vehicleId"`
+}
+
+// VehicleNotice is the payload of ChannelVehicleChanged; Action is one of the Audit* actions.
+type VehicleNotice struct {
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+}
+
+// Notifier fans Postgres notifications out to in-process subscribers. A single LISTEN
+// connection serves every subscriber, and since the notifications come from the database
+// each replica sees the writes of all the others.
+type Notifier struct {
+	DB *pg.DB
+
+	mu   sync.Mutex
+	subs map[string]map[chan string]struct{}
+}
+
+// notifierBuffer is how many notifications a subscriber may fall behind before it misses some.
+const notifierBuffer = 64
+
+// Run listens until ctx is done. go-pg reconnects the listener by itself; notifications sent
+// while it is disconnected are lost.
+func (n *Notifier) Run(ctx context.Context) {
+	ln := n.DB.Listen(ctx, ChannelMovementCreated, ChannelVehicleChanged)
+	defer ln.Close()
+	ch := ln.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			n.dispatch(msg.Channel, msg.Payload)
+		}
+	}
+}
+
+func (n *Notifier) dispatch(channel, payload string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for c := range n.subs[channel] {
+		select {
+		case c <- payload:
+		default:
+			log.Printf("notify: dropping %s notification for a slow subscriber", channel)
+		}
+	}
+}
+
+// subscribe delivers the payloads of channel until ctx is done, then closes the returned channel.
+func (n *Notifier) subscribe(ctx context.Context, channel string) <-chan string {
+	c := make(chan string, notifierBuffer)
+	n.mu.Lock()
+	if n.subs == nil {
+		n.subs = map[string]map[chan string]struct{}{}
+	}
+	if n.subs[channel] == nil {
+		n.subs[channel] = map[chan string]struct{}{}
+	}
+	n.subs[channel][c] = struct{}{}
+	n.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		n.mu.Lock()
+		delete(n.subs[channel], c)
+		close(c)
+		n.mu.Unlock()
+	}()
+	return c
+}
+
+func decodeNotices[T any](ctx context.Context, raw <-chan string) <-chan T {
+	out := make(chan T, notifierBuffer)
+	go func() {
+		defer close(out)
+		for payload := range raw {
+			var t T
+			if err := json.Unmarshal([]byte(payload), &t); err != nil {
+				log.Printf("notify: bad payload %q: %v", payload, err)
+				continue
+			}
+			select {
+			case out <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// MovementsCreated delivers every movement inserted from now until ctx is done.
+func (n *Notifier) MovementsCreated(ctx context.Context) <-chan MovementNotice {
+	return decodeNotices[MovementNotice](ctx, n.subscribe(ctx, ChannelMovementCreated))
+}
+
+// VehiclesChanged delivers every vehicle change from now until ctx is done.
+func (n *Notifier) VehiclesChanged(ctx context.Context) <-chan VehicleNotice {
+	return decodeNotices[VehicleNotice](ctx, n.subscribe(ctx, ChannelVehicleChanged))
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// MaxPageSize caps how many rows a single page may request.
+const MaxPageSize = 100
+
+var ErrInvalidCursor error = apperr.Invalid("after", "is not a valid cursor")
+
+// Cursor is a keyset position: the value of the sort column of a row plus its ID as a tie-breaker.
+// Key names the sort column and Desc its direction so a cursor can't be replayed against a
+// different ordering. It is handed to clients as an opaque base64 string.
+type Cursor struct {
+	Key   string `json:"k"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func timeCursor(key string, desc bool, t time.Time, id int64) Cursor {
+	return Cursor{Key: key, Desc: desc, Value: t.UTC().Format(time.RFC3339Nano), ID: id}
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode. A nil or empty input yields a nil cursor.
+func DecodeCursor(s *string) (*Cursor, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(*s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Key == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// keyset restricts q to rows after c in the (column, id) ordering.
+func keyset(q *orm.Query, c *Cursor, column string, desc bool) (*orm.Query, error) {
+	if c == nil {
+		return q, nil
+	}
+	if c.Key != column || c.Desc != desc {
+		return nil, ErrInvalidCursor
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return q.Where("(?TableAlias.?, ?TableAlias.id) "+op+" (?, ?)", pg.Ident(column), c.Value, c.ID), nil
+}
+
+// PageArgs describes a forward keyset page request. CountTotal asks for Page.TotalCount; without
+// it the count query is skipped and TotalCount is 0.
+type PageArgs struct {
+	First      int
+	After      *Cursor
+	CountTotal bool
+}
+
+// Limit returns First clamped to [1, MaxPageSize]. Callers reject a First below 1 from clients
+// before it gets here.
+func (p PageArgs) Limit() int {
+	switch {
+	case p.First <= 0:
+		return 1
+	case p.First > MaxPageSize:
+		return MaxPageSize
+	}
+	return p.First
+}
+
+// total counts the rows of q if the page asks for it.
+func (p PageArgs) total(q *orm.Query) (int, error) {
+	if !p.CountTotal {
+		return 0, nil
+	}
+	return q.Count()
+}
+
+// Page is one slice of a keyset-paginated list. Cursors[i] is the position of Items[i].
+type Page[T any] struct {
+	Items       []T
+	Cursors     []Cursor
+	TotalCount  int
+	HasNextPage bool
+}
+
+// newPage drops the look-ahead row fetched to detect a following page and computes item cursors.
+func newPage[T any](items []T, limit, total int, cursor func(T) Cursor) *Page[T] {
+	p := &Page[T]{Items: items, TotalCount: total}
+	if len(items) > limit {
+		p.Items = items[:limit]
+		p.HasNextPage = true
+	}
+	p.Cursors = make([]Cursor, len(p.Items))
+	for i, it := range p.Items {
+		p.Cursors[i] = cursor(it)
+	}
+	return p
+}
+
+func userCursor(u *User) Cursor         { return timeCursor("created_at", true, u.CreatedAt, u.ID) }
+func movementCursor(m *Movement) Cursor { return timeCursor("occurred_at", true, m.OccurredAt, m.ID) }
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10/orm"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name string
+		c    Cursor
+	}{
+		{"time ascending", timeCursor("created_at", false, at, 7)},
+		{"time descending", timeCursor("occurred_at", true, at, 42)},
+		{"string value", Cursor{Key: "vin", Value: "1HGCM82633A004352", ID: 3}},
+		{"id only", Cursor{Key: "xid_seq", Value: "0", ID: 99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.c.Encode()
+			got, err := DecodeCursor(&s)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q): %v", s, err)
+			}
+			if *got != tt.c {
+				t.Errorf("round trip = %+v, want %+v", *got, tt.c)
+			}
+		})
+	}
+}
+
+func TestTimeCursorUsesUTC(t *testing.T) {
+	at := time.Date(2024, 3, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	if got, want := timeCursor("created_at", false, at, 1).Value, "2024-03-01T12:00:00Z"; got != want {
+		t.Errorf("Value = %q, want %q", got, want)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		in      *string
+		wantNil bool
+		wantErr bool
+	}{
+		{"nil", nil, true, false},
+		{"empty", str(""), true, false},
+		{"not base64", str("!!!"), false, true},
+		{"not json", str("bm90IGpzb24"), false, true},
+		{"no key", str(Cursor{Value: "x", ID: 1}.Encode()), false, true},
+		{"valid", str(Cursor{Key: "vin", Value: "x", ID: 1}.Encode()), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("err = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (got == nil) != tt.wantNil {
+				t.Errorf("cursor = %+v, want nil: %v", got, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       *Cursor
+		column  string
+		desc    bool
+		wantErr bool
+		want    string
+	}{
+		{name: "no cursor", column: "created_at", want: `FROM "vehicles" AS "vehicle"`},
+		{
+			name: "ascending", c: &Cursor{Key: "vin", Value: "ABC", ID: 5}, column: "vin",
+			want: `("vehicle"."vin", "vehicle".id) > ('ABC', 5)`,
+		},
+		{
+			name: "descending", c: &Cursor{Key: "mileage", Desc: true, Value: "100", ID: 9}, column: "mileage", desc: true,
+			want: `("vehicle"."mileage", "vehicle".id) < ('100', 9)`,
+		},
+		{name: "other column", c: &Cursor{Key: "vin", Value: "ABC", ID: 5}, column: "name", wantErr: true},
+		{name: "other direction", c: &Cursor{Key: "vin", Value: "ABC", ID: 5}, column: "vin", desc: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := keyset(orm.NewQuery(nil, (*Vehicle)(nil)), tt.c, tt.column, tt.desc)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("err = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sql := selectSQL(t, q); !strings.Contains(sql, tt.want) {
+				t.Errorf("query %s\ndoes not contain %s", sql, tt.want)
+			}
+		})
+	}
+}
+
+// selectSQL renders q as the SELECT statement the database would receive.
+func selectSQL(t *testing.T, q *orm.Query) string {
+	t.Helper()
+	fmter := orm.NewFormatter().WithModel(q)
+	b, err := orm.NewSelectQuery(q).AppendQuery(fmter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(fmter.FormatQuery(nil, string(b)))
+}
+
+func TestPageArgsLimit(t *testing.T) {
+	tests := []struct{ first, want int }{
+		{-5, 1},
+		{0, 1},
+		{1, 1},
+		{20, 20},
+		{MaxPageSize, MaxPageSize},
+		{MaxPageSize + 1, MaxPageSize},
+	}
+	for _, tt := range tests {
+		if got := (PageArgs{First: tt.first}).Limit(); got != tt.want {
+			t.Errorf("Limit() with First %d = %d, want %d", tt.first, got, tt.want)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	cursor := func(n int) Cursor { return Cursor{Key: "n", ID: int64(n)} }
+	tests := []struct {
+		name     string
+		items    []int
+		limit    int
+		wantLen  int
+		wantNext bool
+	}{
+		{"empty", nil, 3, 0, false},
+		{"short", []int{1, 2}, 3, 2, false},
+		{"exact", []int{1, 2, 3}, 3, 3, false},
+		{"look-ahead row", []int{1, 2, 3, 4}, 3, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPage(tt.items, tt.limit, 10, cursor)
+			if len(p.Items) != tt.wantLen || len(p.Cursors) != tt.wantLen {
+				t.Fatalf("got %d items and %d cursors, want %d", len(p.Items), len(p.Cursors), tt.wantLen)
+			}
+			if p.HasNextPage != tt.wantNext {
+				t.Errorf("HasNextPage = %v, want %v", p.HasNextPage, tt.wantNext)
+			}
+			for i, it := range p.Items {
+				if p.Cursors[i] != cursor(it) {
+					t.Errorf("Cursors[%d] = %+v, want %+v", i, p.Cursors[i], cursor(it))
+				}
+			}
+			if p.TotalCount != 10 {
+				t.Errorf("TotalCount = %d, want 10", p.TotalCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// Permissions are "<resource>:<action>" names checked by the GraphQL @hasPermission directive
+// and the export endpoints.
+const (
+	PermVehicleRead        = "vehicle:read"
+	PermVehicleReadDeleted = "vehicle:read_deleted"
+	PermVehicleCreate      = "vehicle:create"
+	PermVehicleUpdate      = "vehicle:update"
+	PermVehicleDelete      = "vehicle:delete"
+	PermVehicleRestore     = "vehicle:restore"
+	PermVehiclePurge       = "vehicle:purge"
+	PermVehicleImport      = "vehicle:import"
+	PermVehicleExport      = "vehicle:export"
+	PermMovementRead       = "movement:read"
+	PermMovementCreate     = "movement:create"
+	PermMovementUpdate     = "movement:update"
+	PermMovementCorrect    = "movement:correct"
+	PermLocationManage     = "location:manage"
+	PermMovementSchema     = "movement_schema:manage"
+	PermMovementTypeManage = "movement_type:manage"
+	PermReportRead         = "report:read"
+	PermAuditRead          = "audit:read"
+	PermEventRead          = "event:read"
+	PermUserRead           = "user:read"
+	PermUserManage         = "user:manage"
+	PermRoleManage         = "role:manage"
+	PermWebhookManage      = "webhook:manage"
+)
+
+// AllPermissions describes every permission the API knows about, in display order.
+var AllPermissions = []Permission{
+	{Name: PermVehicleRead, Description: "View vehicles, their movements and history"},
+	{Name: PermVehicleReadDeleted, Description: "Include soft-deleted vehicles in lists and exports"},
+	{Name: PermVehicleCreate, Description: "Create vehicles"},
+	{Name: PermVehicleUpdate, Description: "Edit vehicles"},
+	{Name: PermVehicleDelete, Description: "Soft-delete vehicles"},
+	{Name: PermVehicleRestore, Description: "Restore soft-deleted vehicles"},
+	{Name: PermVehiclePurge, Description: "Permanently remove deleted vehicles"},
+	{Name: PermVehicleImport, Description: "Bulk import vehicles"},
+	{Name: PermVehicleExport, Description: "Download vehicle and movement exports"},
+	{Name: PermMovementRead, Description: "View movements"},
+	{Name: PermMovementCreate, Description: "Record movements"},
+	{Name: PermMovementUpdate, Description: "Edit movements shortly after they are recorded"},
+	{Name: PermMovementCorrect, Description: "Void and reverse movements, and edit them at any time"},
+	{Name: PermMovementSchema, Description: "Set the JSON Schemas movement metadata must satisfy"},
+	{Name: PermMovementTypeManage, Description: "Create, edit and delete custom movement types"},
+	{Name: PermLocationManage, Description: "Create, edit and delete locations"},
+	{Name: PermReportRead, Description: "View movement reports"},
+	{Name: PermAuditRead, Description: "View the global audit log"},
+	{Name: PermEventRead, Description: "Tail the domain event log"},
+	{Name: PermUserRead, Description: "List users"},
+	{Name: PermUserManage, Description: "Change user roles"},
+	{Name: PermRoleManage, Description: "Create roles and edit their permissions"},
+	{Name: PermWebhookManage, Description: "Manage webhooks and redeliver their events"},
+}
+
+// DefaultRolePermissions is what SeedBase grants the built-in roles, each once: a default added
+// here is granted on the next start, and one an Admin revoked is not granted again. Admin always
+// holds every permission and cannot be edited, so nobody can lock themselves out.
+var DefaultRolePermissions = map[string][]string{
+	RoleEditor: {
+		PermVehicleRead, PermVehicleCreate, PermVehicleUpdate, PermVehicleImport, PermVehicleExport,
+		PermMovementRead, PermMovementCreate, PermMovementUpdate, PermLocationManage, PermReportRead,
+	},
+	RoleViewer: {
+		PermVehicleRead, PermVehicleExport, PermMovementRead, PermReportRead,
+	},
+}
+
+// IsBuiltinRole reports whether name is one of the roles seeded by SeedBase.
+func IsBuiltinRole(name string) bool {
+	return name == RoleAdmin || name == RoleEditor || name == RoleViewer
+}
+
+func isPermission(name string) bool {
+	return slices.ContainsFunc(AllPermissions, func(p Permission) bool { return p.Name == name })
+}
+
+func (r *Repos) ListPermissions(ctx context.Context) ([]*Permission, error) {
+	var perms []*Permission
+	err := r.DB.Model(&perms).Order("name ASC").Select()
+	return perms, err
+}
+
+// ListRoles returns every role with its permissions.
+func (r *Repos) ListRoles(ctx context.Context) ([]*Role, error) {
+	var roles []*Role
+	err := r.DB.Model(&roles).
+		Relation("Permissions", func(q *orm.Query) (*orm.Query, error) { return q.Order("name ASC"), nil }).
+		Order("id ASC").
+		Select()
+	return roles, err
+}
+
+// RolePermissionNames returns the permission names granted to each role, keyed by role name.
+func (r *Repos) RolePermissionNames(ctx context.Context) (map[string][]string, error) {
+	var rows []struct {
+		Role       string
+		Permission string
+	}
+	_, err := r.DB.Query(&rows, `
+	  SELECT r.name AS role, p.name AS permission
+	  FROM role_permissions rp
+	  JOIN roles r ON r.id = rp.role_id
+	  JOIN permissions p ON p.id = rp.permission_id`)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]string{}
+	for _, row := range rows {
+		out[row.Role] = append(out[row.Role], row.Permission)
+	}
+	return out, nil
+}
+
+// GetPermissionNamesByRoleIDs returns the sorted permission names of each role; roles without
+// grants map to an empty slice.
+func (r *Repos) GetPermissionNamesByRoleIDs(ctx context.Context, ids []int64) (map[int64][]string, error) {
+	var rows []struct {
+		RoleID int64
+		Name   string
+	}
+	_, err := r.DB.Query(&rows, `
+	  SELECT rp.role_id, p.name
+	  FROM role_permissions rp
+	  JOIN permissions p ON p.id = rp.permission_id
+	  WHERE rp.role_id IN (?)
+	  ORDER BY p.name`, pg.In(ids))
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64][]string, len(ids))
+	for _, id := range ids {
+		out[id] = []string{}
+	}
+	for _, row := range rows {
+		out[row.RoleID] = append(out[row.RoleID], row.Name)
+	}
+	return out, nil
+}
+
+// CreateRole adds a custom role with the given permissions.
+func (r *Repos) CreateRole(ctx context.Context, name string, perms []string) (*Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperr.Invalid("name", "is required")
+	}
+	ro := &Role{Name: name}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if _, err := tx.DB.Model(ro).Insert(); err != nil {
+			return conflict(err, "role %s already exists", name)
+		}
+		return tx.grant(ro.ID, perms)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getRoleWithPermissions(ctx, ro.ID)
+}
+
+// SetRolePermissions replaces the permissions of a role. Admin is not editable.
+func (r *Repos) SetRolePermissions(ctx context.Context, name string, perms []string) (*Role, error) {
+	if name == RoleAdmin {
+		return nil, apperr.Forbidden("built-in role %s always holds every permission", RoleAdmin)
+	}
+	ro, err := r.GetRoleByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	err = r.InTx(ctx, func(tx *Repos) error {
+		if _, err := tx.DB.Model((*RolePermission)(nil)).Where("role_id = ?", ro.ID).Delete(); err != nil {
+			return err
+		}
+		return tx.grant(ro.ID, perms)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.getRoleWithPermissions(ctx, ro.ID)
+}
+
+// DeleteRole removes a custom role. It fails while users are still assigned to it. The role row
+// is locked while users are counted, so a concurrent UpdateUserRole either finishes first and is
+// counted or fails on the foreign key once the role is gone.
+func (r *Repos) DeleteRole(ctx context.Context, name string) error {
+	if IsBuiltinRole(name) {
+		return apperr.Forbidden("built-in role %s cannot be deleted", name)
+	}
+	return r.InTx(ctx, func(tx *Repos) error {
+		var ro Role
+		if err := tx.DB.Model(&ro).Where("name = ?", name).For("UPDATE").Select(); err != nil {
+			return notFound(err, "role %s not found", name)
+		}
+		n, err := tx.DB.Model((*User)(nil)).Where("role_id = ?", ro.ID).Count()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return apperr.Conflict("role %s is still assigned to %d user(s)", name, n)
+		}
+		_, err = tx.DB.Model(&ro).WherePK().Delete()
+		return inUse(err, "role %s is still assigned to users", name)
+	})
+}
+
+func (r *Repos) grant(roleID int64, perms []string) error {
+	for _, p := range perms {
+		if !isPermission(p) {
+			return apperr.Invalid("permissions", "unknown permission %s", p)
+		}
+	}
+	if len(perms) == 0 {
+		return nil
+	}
+	_, err := r.DB.Exec(`
+	  INSERT INTO role_permissions (role_id, permission_id)
+	  SELECT ?, id FROM permissions WHERE name IN (?)
+	  ON CONFLICT DO NOTHING`, roleID, pg.In(perms))
+	return err
+}
+
+func (r *Repos) getRoleWithPermissions(ctx context.Context, id int64) (*Role, error) {
+	var ro Role
+	err := r.DB.Model(&ro).Relation("Permissions").Where("?TableAlias.id = ?", id).Select()
+	if err != nil {
+		return nil, err
+	}
+	return &ro, nil
+}
+
+// Authorizer answers "may this role do that?" from the role_permissions table. Grants are cached
+// for a short while; Invalidate drops the cache after a local change.
+type Authorizer struct {
+	Repos *Repos
+	TTL   time.Duration
+
+	mu       sync.RWMutex
+	grants   map[string]map[string]bool
+	loadedAt time.Time
+}
+
+const defaultAuthorizerTTL = 30 * time.Second
+
+// Can reports whether role holds perm.
+func (a *Authorizer) Can(ctx context.Context, role, perm string) (bool, error) {
+	if role == "" {
+		return false, nil
+	}
+	grants, err := a.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return grants[role][perm], nil
+}
+
+// Invalidate forces the next check to reload grants from the database.
+func (a *Authorizer) Invalidate() {
+	a.mu.Lock()
+	a.grants = nil
+	a.mu.Unlock()
+}
+
+func (a *Authorizer) load(ctx context.Context) (map[string]map[string]bool, error) {
+	ttl := a.TTL
+	if ttl == 0 {
+		ttl = defaultAuthorizerTTL
+	}
+	a.mu.RLock()
+	grants, fresh := a.grants, time.Since(a.loadedAt) < ttl
+	a.mu.RUnlock()
+	if grants != nil && fresh {
+		return grants, nil
+	}
+
+	names, err := a.Repos.RolePermissionNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	grants = make(map[string]map[string]bool, len(names))
+	for role, perms := range names {
+		set := make(map[string]bool, len(perms))
+		for _, p := range perms {
+			set[p] = true
+		}
+		grants[role] = set
+	}
+	a.mu.Lock()
+	a.grants, a.loadedAt = grants, time.Now()
+	a.mu.Unlock()
+	return grants, nil
+}
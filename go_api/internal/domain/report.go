@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// Report intervals, bucketed in UTC. Weeks start on Monday.
+const (
+	IntervalDay   = "DAY"
+	IntervalWeek  = "WEEK"
+	IntervalMonth = "MONTH"
+)
+
+// Report groupings and the column each one groups by.
+var reportGroups = map[string]string{
+	"TYPE":          "m.type",
+	"MODEL_CODE":    "v.model_code",
+	"BATCH_NUMBER":  "v.batch_number",
+	"TRACTION_TYPE": "v.traction_type",
+}
+
+const (
+	// MaxReportRange bounds the time range of any movement report.
+	MaxReportRange = 10 * 366 * 24 * time.Hour
+	// MaxReportBuckets bounds how many buckets a time series may span.
+	MaxReportBuckets = 1000
+)
+
+// checkReportRange validates a report's [from, to) range.
+func checkReportRange(from, to time.Time) error {
+	if !from.Before(to) {
+		return apperr.Invalid("to", "must be after from")
+	}
+	if to.Sub(from) > MaxReportRange {
+		return apperr.Invalid("to", "must be at most 10 years after from")
+	}
+	return nil
+}
+
+// SeriesArgs selects a movement time series. GroupBy is empty or a key of reportGroups.
+type SeriesArgs struct {
+	From, To time.Time
+	Interval string
+	GroupBy  string
+	ZeroFill bool // include empty buckets, for every group that has any movements
+}
+
+// SeriesPoint is the number of movements in the bucket starting at Start.
+type SeriesPoint struct {
+	Start time.Time `pg:"bucket"`
+	Key   string    `pg:"key"`
+	Count int       `pg:"count"`
+}
+
+// MovementSeries is one group of a time series; Key is empty when nothing was grouped.
+type MovementSeries struct {
+	Key    string
+	Total  int
+	Points []SeriesPoint
+}
+
+func bucketCount(from, to time.Time, interval string) int {
+	switch interval {
+	case IntervalWeek:
+		return int(to.Sub(from)/(7*24*time.Hour)) + 2
+	case IntervalMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+	return int(to.Sub(from)/(24*time.Hour)) + 2
+}
+
+// MovementTimeSeries counts movements per interval bucket within [a.From, a.To), split by
+// a.GroupBy. Series are ordered by total, largest first. Like MovementReport, it leaves out void,
+// reversed and reversing movements.
+func (r *Repos) MovementTimeSeries(ctx context.Context, a SeriesArgs) ([]MovementSeries, error) {
+	if err := checkReportRange(a.From, a.To); err != nil {
+		return nil, err
+	}
+	if !slices.Contains([]string{IntervalDay, IntervalWeek, IntervalMonth}, a.Interval) {
+		return nil, apperr.Invalid("interval", "must be DAY, WEEK or MONTH")
+	}
+	if n := bucketCount(a.From, a.To, a.Interval); n > MaxReportBuckets {
+		return nil, apperr.Invalid("to", "range spans %d buckets; use a longer interval or a shorter range (at most %d)", n, MaxReportBuckets)
+	}
+	key := "''"
+	if a.GroupBy != "" {
+		col, ok := reportGroups[a.GroupBy]
+		if !ok {
+			return nil, apperr.Invalid("groupBy", "is not a known grouping")
+		}
+		key = col
+	}
+
+	// ?0 is the date_trunc unit, ?1 and ?2 the range. Buckets are computed on UTC wall time and
+	// converted back to timestamptz.
+	query := fmt.Sprintf(`
+	  WITH counts AS (
+	    SELECT date_trunc(?0, m.occurred_at AT TIME ZONE 'UTC') AS bucket, %s AS key, COUNT(*)::int AS count
+	    FROM movements m JOIN vehicles v ON v.id = m.vehicle_id
+	    WHERE m.occurred_at >= ?1 AND m.occurred_at < ?2 AND %s
+	    GROUP BY 1, 2
+	  )`, key, counted("m"))
+	if a.ZeroFill {
+		keys := "SELECT DISTINCT key FROM counts"
+		if a.GroupBy == "" {
+			keys = "SELECT ''::text AS key"
+		}
+		query += fmt.Sprintf(`,
+	  buckets AS (
+	    SELECT generate_series(
+	      date_trunc(?0, ?1::timestamptz AT TIME ZONE 'UTC'),
+	      ?2::timestamptz AT TIME ZONE 'UTC' - interval '1 microsecond',
+	      ('1 ' || ?0)::interval) AS bucket
+	  ),
+	  keys AS (%s)
+	  SELECT b.bucket AT TIME ZONE 'UTC' AS bucket, k.key, COALESCE(c.count, 0) AS count
+	  FROM buckets b CROSS JOIN keys k
+	  LEFT JOIN counts c ON c.bucket = b.bucket AND c.key = k.key
+	  ORDER BY k.key, b.bucket`, keys)
+	} else {
+		query += `
+	  SELECT bucket AT TIME ZONE 'UTC' AS bucket, key, count FROM counts ORDER BY key, bucket`
+	}
+
+	var points []SeriesPoint
+	unit := map[string]string{IntervalDay: "day", IntervalWeek: "week", IntervalMonth: "month"}[a.Interval]
+	if _, err := r.DB.QueryContext(ctx, &points, query, unit, a.From, a.To); err != nil {
+		return nil, err
+	}
+	var out []MovementSeries
+	for _, p := range points {
+		if len(out) == 0 || out[len(out)-1].Key != p.Key {
+			out = append(out, MovementSeries{Key: p.Key})
+		}
+		s := &out[len(out)-1]
+		s.Total += p.Count
+		s.Points = append(s.Points, p)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Total > out[j].Total })
+	return out, nil
+}
@@ -4,10 +4,33 @@ import (
 	"context"
 	"time"
 
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
 	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
 )
 
-type Repos struct{ DB *pg.DB }
+// Repos wraps a database handle: either the connection pool (*pg.DB) or an open transaction (*pg.Tx).
+// Rules configures vehicle validation; MovementEditWindow is how long after recording a movement
+// UpdateMovement accepts ordinary edits.
+type Repos struct {
+	DB                 orm.DB
+	Rules              VehicleRules
+	MovementEditWindow time.Duration
+}
+
+// InTx runs fn with repos bound to a transaction. If r is already bound to one, fn joins it.
+// It is the domain's counterpart of db.WithTx, which it cannot call since package db imports domain.
+func (r *Repos) InTx(ctx context.Context, fn func(tx *Repos) error) error {
+	db, ok := r.DB.(*pg.DB)
+	if !ok {
+		return fn(r)
+	}
+	return db.RunInTransaction(ctx, func(tx *pg.Tx) error {
+		txr := *r
+		txr.DB = tx
+		return fn(&txr)
+	})
+}
 
 func (r *Repos) GetUserByEmail(ctx context.Context, email string) (*User, error) {
     var u User
@@ -18,36 +41,69 @@ func (r *Repos) GetUserByEmail(ctx context.Context, email string) (*User, error)
     return &u, nil
 }
 
-func (r *Repos) ListUsers(ctx context.Context, limit, offset int) ([]*User, error) {
-    var users []*User
-    err := r.DB.Model(&users).Relation("Role").Order("created_at DESC").Limit(limit).Offset(offset).Select()
-    return users, err
+// ListUsers returns users newest first, using (created_at, id) as the keyset.
+func (r *Repos) ListUsers(ctx context.Context, page PageArgs) (*Page[*User], error) {
+	var users []*User
+	q := r.DB.Model(&users).Relation("Role")
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = keyset(q, page.After, "created_at", true); err != nil {
+		return nil, err
+	}
+	limit := page.Limit()
+	err = q.OrderExpr("?TableAlias.created_at DESC, ?TableAlias.id DESC").Limit(limit + 1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return newPage(users, limit, total, userCursor), nil
 }
 
 func (r *Repos) GetUserByUID(ctx context.Context, uid int64) (*User, error) {
 	var u User
-	err := r.DB.Model(&u).Relation("Role").Where("id = ?", uid).Limit(1).Select()
+	err := r.DB.Model(&u).Relation("Role").Where("?TableAlias.id = ?", uid).Limit(1).Select()
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "user with id %d not found", uid)
 	}
 	return &u, nil
 }
 
 func (r *Repos) CreateUserViewer(ctx context.Context, email, passwordHash string, viewerRoleID int64) (*User, error) {
 	u := &User{Email: email, PasswordHash: passwordHash, RoleID: viewerRoleID}
-	_, err := r.DB.Model(u).Insert()
-	return u, err
+	if _, err := r.DB.Model(u).Insert(); err != nil {
+		return nil, conflict(err, "user with email %s already exists", email)
+	}
+	return u, nil
 }
 
-func (r *Repos) UpdateUserRole(ctx context.Context, userID, newRoleID int64) error {
-	_, err := r.DB.Model(&User{ID: userID, RoleID: newRoleID}).Column("role_id").WherePK().Update()
-	return err
+// UpdateUserRole moves the user to newRoleID and records the change in the event log,
+// attributed to actorID.
+func (r *Repos) UpdateUserRole(ctx context.Context, userID, newRoleID, actorID int64) error {
+	return r.InTx(ctx, func(tx *Repos) error {
+		var u User
+		if err := tx.DB.Model(&u).Where("id = ?", userID).For("UPDATE").Select(); err != nil {
+			return notFound(err, "user with id %d not found", userID)
+		}
+		if u.RoleID == newRoleID {
+			return nil
+		}
+		oldRoleID := u.RoleID
+		u.RoleID = newRoleID
+		if _, err := tx.DB.Model(&u).Column("role_id").WherePK().Update(); err != nil {
+			return inUse(err, "role with id %d was deleted", newRoleID)
+		}
+		return tx.recordEvent(ctx, &DomainEvent{
+			Type: EventUserRoleChanged, AggregateType: AggregateUser, AggregateID: userID, ActorID: actorID,
+			Payload: map[string]any{"userId": userID, "oldRoleId": oldRoleID, "newRoleId": newRoleID},
+		})
+	})
 }
 
 func (r *Repos) GetRoleByName(ctx context.Context, name string) (*Role, error) {
 	var ro Role
 	if err := r.DB.Model(&ro).Where("name = ?", name).Select(); err != nil {
-		return nil, err
+		return nil, notFound(err, "role %s not found", name)
 	}
 	return &ro, nil
 }
@@ -55,31 +111,194 @@ func (r *Repos) GetRoleByName(ctx context.Context, name string) (*Role, error) {
 func (r *Repos) GetRoleByID(ctx context.Context, id int64) (*Role, error) {
 	var ro Role
 	if err := r.DB.Model(&ro).Where("id = ?", id).Select(); err != nil {
-		return nil, err
+		return nil, notFound(err, "role with id %d not found", id)
 	}
 	return &ro, nil
 }
 
-func (r *Repos) CreateVehicle(ctx context.Context, v *Vehicle) (*Vehicle, error) {
-	_, err := r.DB.Model(v).Insert()
+// CreateVehicle validates and inserts v as ACTIVE and records the creation in the audit trail,
+// attributed to actorID. A VIN already in use, even by a deleted vehicle, is a conflict.
+func (r *Repos) CreateVehicle(ctx context.Context, v *Vehicle, actorID int64) (*Vehicle, error) {
+	v.VIN = NormalizeVIN(v.VIN)
+	v.Status = StatusActive
+	if err := fillFromVIN(v, time.Now()); err != nil {
+		return nil, err
+	}
+	if errs := r.vehicleRules().ValidateVehicle(v, nil, time.Now()); len(errs) > 0 {
+		return nil, apperr.Validation(errs...)
+	}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if v.CurrentLocationID != 0 {
+			if err := tx.lockLocation(ctx, v.CurrentLocationID, "locationId"); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.DB.Model(v).Insert(); err != nil {
+			return conflict(err, "vehicle with vin %s already exists", v.VIN)
+		}
+		if err := tx.recordVehicleAudit(v.ID, AuditCreate, actorID, nil, v); err != nil {
+			return err
+		}
+		return tx.recordVehicleEvent(ctx, EventVehicleCreated, v, actorID, nil)
+	})
 	return v, err
 }
 
-func (r *Repos) UpdateVehicle(ctx context.Context, v *Vehicle) (*Vehicle, error) {
-	_, err := r.DB.Model(v).WherePK().Update()
-	return v, err
+// VehicleChanges lists the fields UpdateVehicle sets. Nil fields are left as they are. Status and
+// location are not included: they change only through movements.
+type VehicleChanges struct {
+	Name         *string
+	ModelCode    *string
+	TractionType *string
+	ReleaseYear  *int
+	BatchNumber  *string
+	Color        *string
+	Mileage      *int
+	Manufacturer *string
+	PlantCode    *string
+}
+
+func (c VehicleChanges) apply(v *Vehicle) {
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	set(&v.Name, c.Name)
+	set(&v.ModelCode, c.ModelCode)
+	set(&v.TractionType, c.TractionType)
+	set(&v.BatchNumber, c.BatchNumber)
+	set(&v.Color, c.Color)
+	set(&v.Manufacturer, c.Manufacturer)
+	set(&v.PlantCode, c.PlantCode)
+	if c.ReleaseYear != nil {
+		v.ReleaseYear = *c.ReleaseYear
+	}
+	if c.Mileage != nil {
+		v.Mileage = *c.Mileage
+	}
+}
+
+// UpdateVehicle applies c to the vehicle with the given id while it is locked and saves the fields
+// that changed, recording them in the audit trail, attributed to actorID, and in the event log as
+// VEHICLE_UPDATED.
+func (r *Repos) UpdateVehicle(ctx context.Context, id int64, c VehicleChanges, actorID int64) (*Vehicle, error) {
+	var v Vehicle
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if err := tx.DB.Model(&v).Where("id = ?", id).For("UPDATE").Select(); err != nil {
+			return notFound(err, "vehicle with id %d not found", id)
+		}
+		before := v
+		c.apply(&v)
+		return tx.saveVehicle(ctx, &before, &v, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// vehicleColumns maps the fields of vehicleFields to their columns.
+var vehicleColumns = map[string]string{
+	"vin": "vin", "name": "name", "modelCode": "model_code", "tractionType": "traction_type",
+	"releaseYear": "release_year", "batchNumber": "batch_number", "color": "color", "mileage": "mileage",
+	"status": "status", "manufacturer": "manufacturer", "plantCode": "plant_code",
+	"currentLocationId": "current_location_id",
+}
+
+// saveVehicle validates v, a changed copy of before, and writes only the columns that differ, so
+// it never undoes changes it did not make. before must be the row as locked in this transaction.
+// The changes are audited and published as for UpdateVehicle; nothing is written when there are none.
+func (r *Repos) saveVehicle(ctx context.Context, before, v *Vehicle, actorID int64) error {
+	changes := diffVehicles(before, v)
+	if len(changes) == 0 {
+		return nil
+	}
+	if errs := r.vehicleRules().ValidateVehicle(v, before, time.Now()); len(errs) > 0 {
+		return apperr.Validation(errs...)
+	}
+	columns := []string{"updated_at"}
+	for f := range changes {
+		columns = append(columns, vehicleColumns[f])
+	}
+	v.UpdatedAt = time.Now()
+	// Callers have locked the row, which may be soft-deleted when a batch is renamed.
+	if _, err := r.DB.Model(v).Column(columns...).WherePK().AllWithDeleted().Update(); err != nil {
+		return err
+	}
+	if err := r.recordVehicleAudit(v.ID, AuditUpdate, actorID, before, v); err != nil {
+		return err
+	}
+	return r.recordVehicleEvent(ctx, EventVehicleUpdated, v, actorID, changes)
+}
+
+// DeleteVehicle soft-deletes the vehicle, keeping it and its movements restorable,
+// and records its last values in the audit trail, attributed to actorID.
+func (r *Repos) DeleteVehicle(ctx context.Context, id int64, actorID int64) error {
+	return r.InTx(ctx, func(tx *Repos) error {
+		var before Vehicle
+		if err := tx.DB.Model(&before).Where("id = ?", id).For("UPDATE").Select(); err != nil {
+			return notFound(err, "vehicle with id %d not found", id)
+		}
+		if _, err := tx.DB.Model(&Vehicle{ID: id}).WherePK().Delete(); err != nil {
+			return err
+		}
+		if err := tx.recordVehicleAudit(id, AuditDelete, actorID, &before, nil); err != nil {
+			return err
+		}
+		return tx.recordVehicleEvent(ctx, EventVehicleDeleted, &before, actorID, nil)
+	})
+}
+
+// RestoreVehicle undoes DeleteVehicle. It fails with a not-found error unless the vehicle is soft-deleted.
+func (r *Repos) RestoreVehicle(ctx context.Context, id int64, actorID int64) (*Vehicle, error) {
+	var v Vehicle
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if err := tx.DB.Model(&v).Where("id = ?", id).Deleted().For("UPDATE").Select(); err != nil {
+			return notFound(err, "deleted vehicle with id %d not found", id)
+		}
+		v.DeletedAt = nil
+		v.UpdatedAt = time.Now()
+		if _, err := tx.DB.Model(&v).Column("deleted_at", "updated_at").WherePK().AllWithDeleted().Update(); err != nil {
+			return err
+		}
+		if err := tx.recordVehicleAudit(id, AuditRestore, actorID, nil, &v); err != nil {
+			return err
+		}
+		return tx.recordVehicleEvent(ctx, EventVehicleRestored, &v, actorID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
 }
 
-func (r *Repos) DeleteVehicle(ctx context.Context, id int64) error {
-	_, err := r.DB.Model(&Vehicle{ID: id}).WherePK().Delete()
-	return err
+// PurgeVehicle permanently removes a soft-deleted vehicle together with its movements.
+// It fails with a not-found error unless the vehicle was deleted first.
+func (r *Repos) PurgeVehicle(ctx context.Context, id int64, actorID int64) error {
+	return r.InTx(ctx, func(tx *Repos) error {
+		var before Vehicle
+		if err := tx.DB.Model(&before).Where("id = ?", id).Deleted().For("UPDATE").Select(); err != nil {
+			return notFound(err, "deleted vehicle with id %d not found; delete it before purging", id)
+		}
+		if _, err := tx.DB.Model((*Movement)(nil)).Where("vehicle_id = ?", id).Delete(); err != nil {
+			return err
+		}
+		if _, err := tx.DB.Model(&Vehicle{ID: id}).WherePK().ForceDelete(); err != nil {
+			return err
+		}
+		if err := tx.recordVehicleAudit(id, AuditPurge, actorID, &before, nil); err != nil {
+			return err
+		}
+		return tx.recordVehicleEvent(ctx, EventVehiclePurged, &before, actorID, nil)
+	})
 }
 
 func (r *Repos) GetVehicleByID(ctx context.Context, id int64) (*Vehicle, error) {
 	var v Vehicle
 	err := r.DB.Model(&v).Where("id = ?", id).Select()
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "vehicle with id %d not found", id)
 	}
 	return &v, nil
 }
@@ -87,27 +306,69 @@ func (r *Repos) GetVehicleByVin(ctx context.Context, vin string) (*Vehicle, erro
 	var v Vehicle
 	err := r.DB.Model(&v).Where("vin = ?", vin).Select()
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, "vehicle with vin %s not found", vin)
 	}
 	return &v, nil
 }
 
-func (r *Repos) ListVehicles(ctx context.Context, limit, offset int) ([]*Vehicle, error) {
+// ListVehicles returns the vehicles matching filter in sort order, using (sort column, id) as the keyset.
+func (r *Repos) ListVehicles(ctx context.Context, filter VehicleFilter, sort VehicleSort, page PageArgs) (*Page[*Vehicle], error) {
 	var items []*Vehicle
-	err := r.DB.Model(&items).Order("created_at DESC").Limit(limit).Offset(offset).Select()
-	return items, err
+	q := r.DB.Model(&items).Apply(filter.Where)
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = keyset(q, page.After, sort.Column(), sort.Desc); err != nil {
+		return nil, err
+	}
+	limit := page.Limit()
+	err = q.Apply(sort.Order).Limit(limit + 1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return newPage(items, limit, total, sort.Cursor), nil
 }
 
+// CreateMovement inserts m and records it in the event log, in the same transaction.
 func (r *Repos) CreateMovement(ctx context.Context, m *Movement) (*Movement, error) {
-	_, err := r.DB.Model(m).Insert()
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if _, err := tx.DB.Model(m).Insert(); err != nil {
+			return err
+		}
+		return tx.recordEvent(ctx, &DomainEvent{
+			Type: EventMovementCreated, AggregateType: AggregateMovement, AggregateID: m.ID,
+			ActorID: m.CreatedBy, Payload: movementPayload(m),
+		})
+	})
 	return m, err
 }
 
-func (r *Repos) ListMovementsByVehicle(ctx context.Context, vehicleID int64, limit, offset int) ([]*Movement, error) {
+func (r *Repos) GetMovementByID(ctx context.Context, id int64) (*Movement, error) {
+	var m Movement
+	if err := r.DB.ModelContext(ctx, &m).Where("id = ?", id).Select(); err != nil {
+		return nil, notFound(err, "movement with id %d not found", id)
+	}
+	return &m, nil
+}
+
+// ListMovementsByVehicle returns a vehicle's movements latest first, using (occurred_at, id) as the keyset.
+func (r *Repos) ListMovementsByVehicle(ctx context.Context, vehicleID int64, page PageArgs) (*Page[*Movement], error) {
 	var ms []*Movement
-	err := r.DB.Model(&ms).Where("vehicle_id = ?", vehicleID).
-		Order("occurred_at DESC").Limit(limit).Offset(offset).Select()
-	return ms, err
+	q := r.DB.Model(&ms).Where("vehicle_id = ?", vehicleID)
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = keyset(q, page.After, "occurred_at", true); err != nil {
+		return nil, err
+	}
+	limit := page.Limit()
+	err = q.Order("occurred_at DESC", "id DESC").Limit(limit + 1).Select()
+	if err != nil {
+		return nil, err
+	}
+	return newPage(ms, limit, total, movementCursor), nil
 }
 
 // MovementReportRow is a simple report row representing the movement Type and the Count of occurrences
@@ -117,13 +378,96 @@ type MovementReportRow struct {
 	Count int    `pg:"count"`
 }
 
+// MovementReport counts movements per type in [from, to). Void, reversed and reversing movements
+// are left out.
 func (r *Repos) MovementReport(ctx context.Context, from, to time.Time) ([]MovementReportRow, error) {
+	if err := checkReportRange(from, to); err != nil {
+		return nil, err
+	}
 	var rows []MovementReportRow
 	_, err := r.DB.Query(&rows, `
-	  SELECT type, COUNT(*)::int AS count
-	  FROM movements
-	  WHERE occurred_at >= ? AND occurred_at < ?
-	  GROUP BY type
+	  SELECT m.type, COUNT(*)::int AS count
+	  FROM movements m
+	  WHERE m.occurred_at >= ? AND m.occurred_at < ? AND `+counted("m")+`
+	  GROUP BY m.type
 	  ORDER BY count DESC`, from, to)
 	return rows, err
 }
+
+// Batch lookups used by the GraphQL dataloaders. Missing IDs are simply absent from the result.
+
+// GetVehiclesByIDs includes soft-deleted vehicles so their movements still resolve; callers hide
+// them from those without PermVehicleReadDeleted.
+func (r *Repos) GetVehiclesByIDs(ctx context.Context, ids []int64) (map[int64]*Vehicle, error) {
+	var items []*Vehicle
+	if err := r.DB.ModelContext(ctx, &items).Where("id IN (?)", pg.In(ids)).AllWithDeleted().Select(); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]*Vehicle, len(items))
+	for _, v := range items {
+		out[v.ID] = v
+	}
+	return out, nil
+}
+
+func (r *Repos) GetUsersByIDs(ctx context.Context, ids []int64) (map[int64]*User, error) {
+	var items []*User
+	if err := r.DB.ModelContext(ctx, &items).Where("id IN (?)", pg.In(ids)).Select(); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]*User, len(items))
+	for _, u := range items {
+		out[u.ID] = u
+	}
+	return out, nil
+}
+
+func (r *Repos) GetRolesByIDs(ctx context.Context, ids []int64) (map[int64]*Role, error) {
+	var items []*Role
+	if err := r.DB.ModelContext(ctx, &items).Where("id IN (?)", pg.In(ids)).Select(); err != nil {
+		return nil, err
+	}
+	out := make(map[int64]*Role, len(items))
+	for _, ro := range items {
+		out[ro.ID] = ro
+	}
+	return out, nil
+}
+
+type movementWindowRow struct {
+	tableName struct{} `pg:",discard_unknown_columns"`
+	Movement
+	Total int `pg:"total"`
+}
+
+// ListMovementsForVehicles returns the first page of movements for each vehicle in one query,
+// in the same order and shape as ListMovementsByVehicle without a cursor.
+func (r *Repos) ListMovementsForVehicles(ctx context.Context, vehicleIDs []int64, first int) (map[int64]*Page[*Movement], error) {
+	limit := PageArgs{First: first}.Limit()
+	var rows []movementWindowRow
+	_, err := r.DB.QueryContext(ctx, &rows, `
+	  SELECT * FROM (
+	    SELECT m.*,
+	      row_number() OVER (PARTITION BY m.vehicle_id ORDER BY m.occurred_at DESC, m.id DESC) AS rn,
+	      count(*) OVER (PARTITION BY m.vehicle_id)::int AS total
+	    FROM movements m
+	    WHERE m.vehicle_id IN (?)
+	  ) t
+	  WHERE rn <= ?
+	  ORDER BY vehicle_id, occurred_at DESC, id DESC`, pg.In(vehicleIDs), limit+1)
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[int64][]*Movement, len(vehicleIDs))
+	totals := make(map[int64]int, len(vehicleIDs))
+	for i := range rows {
+		m := rows[i].Movement
+		grouped[m.VehicleID] = append(grouped[m.VehicleID], &m)
+		totals[m.VehicleID] = rows[i].Total
+	}
+	out := make(map[int64]*Page[*Movement], len(vehicleIDs))
+	for _, id := range vehicleIDs {
+		out[id] = newPage(grouped[id], limit, totals[id], movementCursor)
+	}
+	return out, nil
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// VehicleSearchHit is a vehicle matched by SearchVehicles along with its relevance score
+// and the spans of each searchable field that matched the query.
+type VehicleSearchHit struct {
+	tableName struct{} `pg:"vehicles,discard_unknown_columns"`
+	Vehicle
+	Score      float64     `pg:"score"`
+	Highlights []Highlight `pg:"-"`
+}
+
+// Highlight lists the matched spans [start, end) within one field value, in UTF-16 code units
+// so that clients can slice Value with JavaScript string indexes.
+type Highlight struct {
+	Field  string
+	Value  string
+	Ranges [][2]int
+}
+
+// searchTokens splits a free-form query into lowercase alphanumeric terms.
+func searchTokens(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchVehicles ranks vehicles against query by combining full-text matches (every term used as a
+// prefix), substring matches on VIN and batch number, and trigram similarity so that misspelled
+// names and model codes still hit.
+func (r *Repos) SearchVehicles(ctx context.Context, query string, limit int) ([]*VehicleSearchHit, error) {
+	tokens := searchTokens(query)
+	if len(tokens) == 0 {
+		return []*VehicleSearchHit{}, nil
+	}
+	tsq := strings.Join(tokens, ":* & ") + ":*"
+	raw := strings.TrimSpace(query)
+	like := containsPattern(raw)
+	if limit <= 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	var hits []*VehicleSearchHit
+	_, err := r.DB.Query(&hits, `
+	  WITH q AS (SELECT to_tsquery('simple', ?0) AS tsq)
+	  SELECT v.*,
+	    ts_rank(v.search_vector, q.tsq)
+	      + greatest(similarity(v.vin, ?1), word_similarity(?1, v.name),
+	                 word_similarity(?1, v.model_code), similarity(v.batch_number, ?1))
+	      + CASE WHEN v.vin ILIKE ?2 OR v.batch_number ILIKE ?2 THEN 1 ELSE 0 END AS score
+	  FROM vehicles v, q
+	  WHERE v.deleted_at IS NULL
+	    AND (v.search_vector @@ q.tsq
+	      OR v.vin ILIKE ?2
+	      OR v.batch_number ILIKE ?2
+	      OR ?1 <% v.name
+	      OR ?1 <% v.model_code)
+	  ORDER BY score DESC, v.id DESC
+	  LIMIT ?3`, tsq, raw, like, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hits {
+		h.Highlights = highlight(tokens, map[string]string{
+			"vin": h.VIN, "name": h.Name, "modelCode": h.ModelCode, "batchNumber": h.BatchNumber,
+		})
+	}
+	return hits, nil
+}
+
+var highlightFields = []string{"vin", "name", "modelCode", "batchNumber"}
+
+// highlight returns, per field, the merged spans where any token occurs case-insensitively.
+// Runes are lowercased one at a time, so matching never shifts offsets in the original value.
+func highlight(tokens []string, values map[string]string) []Highlight {
+	var out []Highlight
+	for _, field := range highlightFields {
+		val := values[field]
+		var lower []rune
+		offsets := []int{0} // offsets[i] is the UTF-16 offset of rune i
+		for _, r := range val {
+			lower = append(lower, unicode.ToLower(r))
+			offsets = append(offsets, offsets[len(offsets)-1]+utf16.RuneLen(r))
+		}
+		var spans [][2]int
+		for _, tok := range tokens {
+			t := []rune(tok)
+			for i := 0; i+len(t) <= len(lower); {
+				if !slices.Equal(lower[i:i+len(t)], t) {
+					i++
+					continue
+				}
+				spans = append(spans, [2]int{offsets[i], offsets[i+len(t)]})
+				i += len(t)
+			}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		out = append(out, Highlight{Field: field, Value: val, Ranges: mergeSpans(spans)})
+	}
+	return out
+}
+
+func mergeSpans(spans [][2]int) [][2]int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s[0] <= last[1] {
+			if s[1] > last[1] {
+				last[1] = s[1]
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// containsPattern returns the LIKE pattern matching strings that contain raw literally.
+func containsPattern(raw string) string {
+	return "%" + strings.ReplaceAll(strings.ReplaceAll(raw, `\`, `\\`), "%", `\%`) + "%"
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchTokens(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{}},
+		{"  ", []string{}},
+		{"Civic", []string{"civic"}},
+		{"civic-2020, B-7", []string{"civic", "2020", "b", "7"}},
+		{"Škoda Octavia", []string{"škoda", "octavia"}},
+	}
+	for _, tt := range tests {
+		if got := searchTokens(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchTokens(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		values map[string]string
+		want   []Highlight
+	}{
+		{
+			name:   "no match",
+			tokens: []string{"zzz"},
+			values: map[string]string{"vin": "1HGCM82633A004352", "name": "Civic"},
+		},
+		{
+			name:   "every occurrence",
+			tokens: []string{"abc"},
+			values: map[string]string{"batchNumber": "xABCyabc"},
+			want:   []Highlight{{Field: "batchNumber", Value: "xABCyabc", Ranges: [][2]int{{1, 4}, {5, 8}}}},
+		},
+		{
+			name:   "fields in a fixed order",
+			tokens: []string{"a1"},
+			values: map[string]string{"batchNumber": "A1", "vin": "A1", "modelCode": "A1"},
+			want: []Highlight{
+				{Field: "vin", Value: "A1", Ranges: [][2]int{{0, 2}}},
+				{Field: "modelCode", Value: "A1", Ranges: [][2]int{{0, 2}}},
+				{Field: "batchNumber", Value: "A1", Ranges: [][2]int{{0, 2}}},
+			},
+		},
+		{
+			name:   "non-BMP characters count as two units",
+			tokens: []string{"civic"},
+			values: map[string]string{"name": "🚗 Civic"},
+			want:   []Highlight{{Field: "name", Value: "🚗 Civic", Ranges: [][2]int{{3, 8}}}},
+		},
+		{
+			name:   "case folding outside ASCII",
+			tokens: []string{"école"},
+			values: map[string]string{"name": "Bus ÉCOLE"},
+			want:   []Highlight{{Field: "name", Value: "Bus ÉCOLE", Ranges: [][2]int{{4, 9}}}},
+		},
+		{
+			name:   "overlapping tokens merge",
+			tokens: []string{"civ", "vic"},
+			values: map[string]string{"name": "Civic"},
+			want:   []Highlight{{Field: "name", Value: "Civic", Ranges: [][2]int{{0, 5}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.tokens, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlight = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans [][2]int
+		want  [][2]int
+	}{
+		{"single", [][2]int{{2, 4}}, [][2]int{{2, 4}}},
+		{"disjoint unsorted", [][2]int{{6, 8}, {0, 2}}, [][2]int{{0, 2}, {6, 8}}},
+		{"overlapping", [][2]int{{0, 3}, {2, 5}}, [][2]int{{0, 5}}},
+		{"adjacent", [][2]int{{0, 2}, {2, 4}}, [][2]int{{0, 4}}},
+		{"contained", [][2]int{{0, 10}, {3, 4}, {12, 13}}, [][2]int{{0, 10}, {12, 13}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeSpans(tt.spans); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeSpans = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	tests := []struct{ raw, want string }{
+		{"B-7", `%B-7%`},
+		{"50%", `%50\%%`},
+		{`a\b`, `%a\\b%`},
+	}
+	for _, tt := range tests {
+		if got := containsPattern(tt.raw); got != tt.want {
+			t.Errorf("containsPattern(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
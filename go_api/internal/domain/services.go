@@ -2,63 +2,196 @@ package domain
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	Repos     *Repos
-	JWTSecret []byte
+	Repos      *Repos
+	JWTSecret  []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
 }
 
-func (s *AuthService) SignupViewer(ctx context.Context, email, password string) (*User, string, error) {
+// Tokens is what a client receives on login or refresh. The access token is a short-lived JWT;
+// the refresh token is opaque and single-use.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+var (
+	ErrInvalidRefreshToken error = apperr.Unauthenticated("invalid refresh token")
+	errInvalidCredentials  error = apperr.Unauthenticated("invalid credentials")
+)
+
+func (s *AuthService) SignupViewer(ctx context.Context, email, password string) (*User, *Tokens, error) {
 	if len(password) < 8 {
-		return nil, "", errors.New("weak password")
+		return nil, nil, apperr.Invalid("password", "must be at least 8 characters")
 	}
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	viewer, err := s.Repos.GetRoleByName(ctx, RoleViewer)
 	if err != nil {
-		return nil, "", fmt.Errorf("resolve role: %w", err)
+		return nil, nil, fmt.Errorf("resolve role: %w", err)
 	}
 	u, err := s.Repos.CreateUserViewer(ctx, email, string(hash), viewer.ID)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, err
 	}
 	u.Role = viewer
-	tok, _ := s.makeJWT(u.ID, RoleViewer)
-	return u, tok, nil
+	toks, err := s.startSession(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, toks, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (*User, string, error) {
+func (s *AuthService) Login(ctx context.Context, email, password string) (*User, *Tokens, error) {
 	u, err := s.Repos.GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		return nil, nil, errInvalidCredentials
 	}
 	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
-		return nil, "", errors.New("invalid credentials")
+		return nil, nil, errInvalidCredentials
 	}
-	roleName := u.Role.Name
-	tok, _ := s.makeJWT(u.ID, roleName)
-	return u, tok, nil
+	toks, err := s.startSession(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, toks, nil
 }
 
-func (s *AuthService) ChangeUserRole(ctx context.Context, actingRole string, userID int64, newRoleName string) error {
-	if actingRole != RoleAdmin {
-		return errors.New("forbidden: only Admin can change roles")
+// Refresh exchanges a refresh token for a new token pair and retires the old one.
+// Presenting any refresh token the session already rotated away from means it leaked, so the
+// whole session is revoked.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*User, *Tokens, error) {
+	hash := hashToken(refreshToken)
+	sess, err := s.Repos.GetSessionByRefreshHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			if old, err := s.Repos.GetSessionBySupersededHash(ctx, hash); err == nil {
+				_ = s.Repos.RevokeSession(ctx, old.ID)
+			}
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+	if sess.RevokedAt != nil || time.Now().After(sess.ExpiresAt) {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	u, err := s.Repos.GetUserByUID(ctx, sess.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	ok, err := s.Repos.RotateSession(ctx, sess.ID, hash, hashToken(refresh), time.Now().Add(s.RefreshTTL))
+	if err != nil {
+		return nil, nil, err
 	}
+	if !ok {
+		// Lost a race with another refresh using the same token.
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	toks, err := s.issue(u, sess.ID, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u, toks, nil
+}
+
+// Logout revokes a single session; access tokens bound to it stop working immediately.
+func (s *AuthService) Logout(ctx context.Context, sessionID int64) error {
+	return s.Repos.RevokeSession(ctx, sessionID)
+}
+
+// LogoutAll revokes every session of the user.
+func (s *AuthService) LogoutAll(ctx context.Context, userID int64) error {
+	return s.Repos.RevokeUserSessions(ctx, userID)
+}
+
+// ChangeUserRole sets the user's role and signs them out everywhere, so no token
+// minted under the old role survives the change. The change is attributed to actorID in the
+// event log. Callers must check PermUserManage.
+func (s *AuthService) ChangeUserRole(ctx context.Context, actorID, userID int64, newRoleName string) error {
 	role, err := s.Repos.GetRoleByName(ctx, newRoleName)
 	if err != nil {
 		return err
 	}
-	return s.Repos.UpdateUserRole(ctx, userID, role.ID)
+	return s.Repos.InTx(ctx, func(tx *Repos) error {
+		if err := tx.UpdateUserRole(ctx, userID, role.ID, actorID); err != nil {
+			return err
+		}
+		return tx.RevokeUserSessions(ctx, userID)
+	})
+}
+
+// SessionRole resolves the current role for a token's session; it fails once the session
+// is revoked or expired. It is the lookup used by httpx.AuthMiddleware.
+func (s *AuthService) SessionRole(ctx context.Context, sessionID, userID int64) (string, error) {
+	return s.Repos.ActiveSessionRole(ctx, sessionID, userID)
+}
+
+func (s *AuthService) startSession(ctx context.Context, u *User) (*Tokens, error) {
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.Repos.CreateSession(ctx, &Session{
+		UserID:      u.ID,
+		RefreshHash: hashToken(refresh),
+		ExpiresAt:   time.Now().Add(s.RefreshTTL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+	return s.issue(u, sess.ID, refresh)
+}
+
+func (s *AuthService) issue(u *User, sessionID int64, refresh string) (*Tokens, error) {
+	role := ""
+	if u.Role != nil {
+		role = u.Role.Name
+	}
+	exp := time.Now().Add(s.AccessTTL)
+	tok, err := s.makeJWT(u.ID, sessionID, role, exp)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{AccessToken: tok, RefreshToken: refresh, ExpiresAt: exp}, nil
 }
 
-func (s *AuthService) makeJWT(uid int64, role string) (string, error) {
-	claims := jwt.MapClaims{"uid": uid, "role": role, "exp": time.Now().Add(24 * time.Hour).Unix()}
+// makeJWT signs an access token. The role claim is informational for clients; the API
+// always re-reads the role through the session.
+func (s *AuthService) makeJWT(uid, sid int64, role string, exp time.Time) (string, error) {
+	claims := jwt.MapClaims{"uid": uid, "sid": sid, "role": role, "exp": exp.Unix()}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return t.SignedString(s.JWTSecret)
 }
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
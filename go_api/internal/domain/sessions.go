@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+func (r *Repos) CreateSession(ctx context.Context, s *Session) (*Session, error) {
+	_, err := r.DB.Model(s).Insert()
+	return s, err
+}
+
+// GetSessionByRefreshHash returns the session currently holding hash, live or not.
+func (r *Repos) GetSessionByRefreshHash(ctx context.Context, hash string) (*Session, error) {
+	var s Session
+	if err := r.DB.Model(&s).Where("refresh_hash = ?", hash).Select(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSessionBySupersededHash finds the session that once held hash and has since rotated away from it.
+func (r *Repos) GetSessionBySupersededHash(ctx context.Context, hash string) (*Session, error) {
+	var s Session
+	err := r.DB.Model(&s).
+		Where("id = (SELECT session_id FROM session_superseded_hashes WHERE hash = ?)", hash).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// RotateSession swaps the refresh hash only if it still equals oldHash, so two concurrent
+// refreshes with the same token cannot both succeed, and keeps oldHash among the session's
+// superseded hashes. It reports whether the swap happened.
+func (r *Repos) RotateSession(ctx context.Context, id int64, oldHash, newHash string, expiresAt time.Time) (bool, error) {
+	var rotated bool
+	err := r.InTx(ctx, func(tx *Repos) error {
+		res, err := tx.DB.Model((*Session)(nil)).
+			Set("refresh_hash = ?, expires_at = ?, rotated_at = now()", newHash, expiresAt).
+			Where("id = ? AND refresh_hash = ? AND revoked_at IS NULL", id, oldHash).
+			Update()
+		if err != nil {
+			return err
+		}
+		if rotated = res.RowsAffected() == 1; !rotated {
+			return nil
+		}
+		_, err = tx.DB.Exec(`INSERT INTO session_superseded_hashes (hash, session_id) VALUES (?, ?)`, oldHash, id)
+		return err
+	})
+	return rotated, err
+}
+
+func (r *Repos) RevokeSession(ctx context.Context, id int64) error {
+	_, err := r.DB.Model((*Session)(nil)).
+		Set("revoked_at = now()").
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update()
+	return err
+}
+
+// RevokeUserSessions signs the user out everywhere.
+func (r *Repos) RevokeUserSessions(ctx context.Context, userID int64) error {
+	_, err := r.DB.Model((*Session)(nil)).
+		Set("revoked_at = now()").
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update()
+	return err
+}
+
+// ActiveSessionRole returns the user's current role name if the session is live and belongs to the user.
+func (r *Repos) ActiveSessionRole(ctx context.Context, sessionID, userID int64) (string, error) {
+	var role string
+	_, err := r.DB.QueryOne(pg.Scan(&role), `
+	  SELECT r.name
+	  FROM sessions s
+	  JOIN users u ON u.id = s.user_id
+	  JOIN roles r ON r.id = u.role_id
+	  WHERE s.id = ? AND s.user_id = ? AND s.revoked_at IS NULL AND s.expires_at > now()`,
+		sessionID, userID)
+	return role, err
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// streamBatchSize is how many rows each FETCH pulls from the server-side cursor.
+const streamBatchSize = 500
+
+// stream runs the query built by build through a server-side cursor inside a transaction and
+// calls fn for every row, so arbitrarily large result sets are never held in memory at once.
+func stream[T any](ctx context.Context, r *Repos, build func(*orm.Query) (*orm.Query, error), fn func(*T) error) error {
+	return r.InTx(ctx, func(tx *Repos) error {
+		q, err := build(tx.DB.ModelContext(ctx, (*T)(nil)))
+		if err != nil {
+			return err
+		}
+		sel, err := orm.NewSelectQuery(q).AppendQuery(tx.DB.Formatter(), nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.DB.ExecContext(ctx, "DECLARE stream_cursor NO SCROLL CURSOR FOR ?", pg.Safe(sel)); err != nil {
+			return err
+		}
+		for {
+			var batch []T
+			res, err := tx.DB.QueryContext(ctx, &batch, "FETCH ? FROM stream_cursor", streamBatchSize)
+			if err != nil {
+				return err
+			}
+			for i := range batch {
+				if err := fn(&batch[i]); err != nil {
+					return err
+				}
+			}
+			if res.RowsReturned() < streamBatchSize {
+				return nil
+			}
+		}
+	})
+}
+
+// StreamVehicles calls fn for every vehicle matching filter, in sort order.
+func (r *Repos) StreamVehicles(ctx context.Context, filter VehicleFilter, sort VehicleSort, fn func(*Vehicle) error) error {
+	return stream(ctx, r, func(q *orm.Query) (*orm.Query, error) {
+		return q.Apply(filter.Where).Apply(sort.Order), nil
+	}, fn)
+}
+
+// StreamMovements calls fn for every movement matching filter, oldest first.
+func (r *Repos) StreamMovements(ctx context.Context, filter MovementFilter, fn func(*Movement) error) error {
+	return stream(ctx, r, func(q *orm.Query) (*orm.Query, error) {
+		return q.Apply(filter.Where).Order("occurred_at ASC", "id ASC"), nil
+	}, fn)
+}
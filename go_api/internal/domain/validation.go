@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// VehicleRules configures vehicle validation. The zero value uses DefaultVehicleRules.
+type VehicleRules struct {
+	MinReleaseYear       int  // earliest accepted release year
+	MaxReleaseYearsAhead int  // how many years past the current one a release year may be
+	VINCheckDigit        bool // verify the ISO 3779 check digit at position 9
+}
+
+var DefaultVehicleRules = VehicleRules{MinReleaseYear: 1886, MaxReleaseYearsAhead: 1, VINCheckDigit: true}
+
+func (r *Repos) vehicleRules() VehicleRules {
+	if r.Rules == (VehicleRules{}) {
+		return DefaultVehicleRules
+	}
+	return r.Rules
+}
+
+// ValidateVehicle checks next against the rules. When before is set, next is an update of it:
+// only fields that changed are checked, so older rows stay editable, and mileage may not go down.
+// Field paths follow the GraphQL VehicleInput.
+func (rules VehicleRules) ValidateVehicle(next, before *Vehicle, now time.Time) []apperr.FieldError {
+	var errs []apperr.FieldError
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, apperr.FieldError{Path: []string{field}, Message: fmt.Sprintf(format, args...)})
+	}
+	var prev Vehicle
+	if before != nil {
+		prev = *before
+	}
+	isNew := before == nil
+
+	if isNew || next.VIN != prev.VIN {
+		if p := vinProblem(next.VIN, rules.VINCheckDigit); p != "" {
+			fail("vin", "%s", p)
+		}
+	}
+	if (isNew || next.Name != prev.Name) && strings.TrimSpace(next.Name) == "" {
+		fail("name", "must not be empty")
+	}
+	if (isNew || next.ModelCode != prev.ModelCode) && strings.TrimSpace(next.ModelCode) == "" {
+		fail("modelCode", "must not be empty")
+	}
+	if (isNew || next.BatchNumber != prev.BatchNumber) && strings.TrimSpace(next.BatchNumber) == "" {
+		fail("batchNumber", "must not be empty")
+	}
+	if isNew || next.ReleaseYear != prev.ReleaseYear {
+		maxYear := now.Year() + rules.MaxReleaseYearsAhead
+		if next.ReleaseYear < rules.MinReleaseYear || next.ReleaseYear > maxYear {
+			fail("releaseYear", "must be between %d and %d", rules.MinReleaseYear, maxYear)
+		}
+	}
+	switch {
+	case next.Mileage < 0:
+		fail("mileage", "must not be negative")
+	case !isNew && next.Mileage < prev.Mileage:
+		fail("mileage", "must not be lower than the current %d", prev.Mileage)
+	}
+	return errs
+}
+
+// maxClockSkew is how far in the future a movement's occurredAt may be, to allow for client clocks.
+const maxClockSkew = 5 * time.Minute
+
+// ValidateMovement checks a movement before it is recorded. Field paths follow the GraphQL MovementInput.
+func ValidateMovement(m *Movement, now time.Time) []apperr.FieldError {
+	var errs []apperr.FieldError
+	switch {
+	case m.OccurredAt.IsZero():
+		errs = append(errs, apperr.FieldError{Path: []string{"occurredAt"}, Message: "is required"})
+	case m.OccurredAt.After(now.Add(maxClockSkew)):
+		errs = append(errs, apperr.FieldError{Path: []string{"occurredAt"}, Message: "must not be in the future"})
+	}
+	return errs
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// VehicleFilter narrows a vehicle query. Nil or empty fields are ignored; all set fields must match.
+// Its Where method plugs into any vehicles query via (*orm.Query).Apply, so lists, exports and
+// reports share the same semantics.
+type VehicleFilter struct {
+	Status          []string
+	TractionType    []string
+	ModelCode       *string
+	ReleaseYearFrom *int
+	ReleaseYearTo   *int
+	BatchNumber     *string
+	Color           *string
+	MileageMin      *int
+	MileageMax      *int
+	CreatedAfter    *time.Time
+	CreatedBefore   *time.Time
+	UpdatedAfter    *time.Time
+	UpdatedBefore   *time.Time
+	LocationID      *int64
+	IncludeDeleted  bool
+}
+
+func (f VehicleFilter) Where(q *orm.Query) (*orm.Query, error) {
+	if f.IncludeDeleted {
+		q = q.AllWithDeleted()
+	}
+	if len(f.Status) > 0 {
+		q = q.Where("?TableAlias.status IN (?)", pg.In(f.Status))
+	}
+	if len(f.TractionType) > 0 {
+		q = q.Where("?TableAlias.traction_type IN (?)", pg.In(f.TractionType))
+	}
+	if f.ModelCode != nil {
+		q = q.Where("?TableAlias.model_code = ?", *f.ModelCode)
+	}
+	if f.ReleaseYearFrom != nil {
+		q = q.Where("?TableAlias.release_year >= ?", *f.ReleaseYearFrom)
+	}
+	if f.ReleaseYearTo != nil {
+		q = q.Where("?TableAlias.release_year <= ?", *f.ReleaseYearTo)
+	}
+	if f.BatchNumber != nil {
+		q = q.Where("?TableAlias.batch_number = ?", *f.BatchNumber)
+	}
+	if f.Color != nil {
+		q = q.Where("lower(?TableAlias.color) = lower(?)", *f.Color)
+	}
+	if f.MileageMin != nil {
+		q = q.Where("?TableAlias.mileage >= ?", *f.MileageMin)
+	}
+	if f.MileageMax != nil {
+		q = q.Where("?TableAlias.mileage <= ?", *f.MileageMax)
+	}
+	if f.CreatedAfter != nil {
+		q = q.Where("?TableAlias.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		q = q.Where("?TableAlias.created_at < ?", *f.CreatedBefore)
+	}
+	if f.UpdatedAfter != nil {
+		q = q.Where("?TableAlias.updated_at >= ?", *f.UpdatedAfter)
+	}
+	if f.UpdatedBefore != nil {
+		q = q.Where("?TableAlias.updated_at < ?", *f.UpdatedBefore)
+	}
+	if f.LocationID != nil {
+		q = q.Where("?TableAlias.current_location_id = ?", *f.LocationID)
+	}
+	return q, nil
+}
+
+// Vehicle sort keys.
+const (
+	SortCreatedAt   = "CREATED_AT"
+	SortUpdatedAt   = "UPDATED_AT"
+	SortName        = "NAME"
+	SortVIN         = "VIN"
+	SortModelCode   = "MODEL_CODE"
+	SortReleaseYear = "RELEASE_YEAR"
+	SortMileage     = "MILEAGE"
+)
+
+var vehicleSortColumns = map[string]string{
+	SortCreatedAt:   "created_at",
+	SortUpdatedAt:   "updated_at",
+	SortName:        "name",
+	SortVIN:         "vin",
+	SortModelCode:   "model_code",
+	SortReleaseYear: "release_year",
+	SortMileage:     "mileage",
+}
+
+// VehicleSort orders a vehicle query by one column, with id as the tie-breaker.
+// The zero value sorts by creation time, oldest first; DefaultVehicleSort is newest first.
+type VehicleSort struct {
+	Field string
+	Desc  bool
+}
+
+var DefaultVehicleSort = VehicleSort{Field: SortCreatedAt, Desc: true}
+
+// Column returns the SQL column behind the sort field.
+func (s VehicleSort) Column() string {
+	if c, ok := vehicleSortColumns[s.Field]; ok {
+		return c
+	}
+	return vehicleSortColumns[SortCreatedAt]
+}
+
+func (s VehicleSort) Order(q *orm.Query) (*orm.Query, error) {
+	dir := "ASC"
+	if s.Desc {
+		dir = "DESC"
+	}
+	return q.OrderExpr(fmt.Sprintf("?TableAlias.? %s, ?TableAlias.id %s", dir, dir), pg.Ident(s.Column())), nil
+}
+
+// Cursor returns the keyset position of v under this sort.
+func (s VehicleSort) Cursor(v *Vehicle) Cursor {
+	col := s.Column()
+	switch col {
+	case "updated_at":
+		return timeCursor(col, s.Desc, v.UpdatedAt, v.ID)
+	case "name":
+		return Cursor{Key: col, Desc: s.Desc, Value: v.Name, ID: v.ID}
+	case "vin":
+		return Cursor{Key: col, Desc: s.Desc, Value: v.VIN, ID: v.ID}
+	case "model_code":
+		return Cursor{Key: col, Desc: s.Desc, Value: v.ModelCode, ID: v.ID}
+	case "release_year":
+		return Cursor{Key: col, Desc: s.Desc, Value: strconv.Itoa(v.ReleaseYear), ID: v.ID}
+	case "mileage":
+		return Cursor{Key: col, Desc: s.Desc, Value: strconv.Itoa(v.Mileage), ID: v.ID}
+	}
+	return timeCursor(col, s.Desc, v.CreatedAt, v.ID)
+}
@@ -0,0 +1,375 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+)
+
+// Allowed values mirroring the GraphQL TractionType and VehicleStatus enums.
+var (
+	TractionTypes   = []string{"RWD", "FWD", "AWD", "FOUR_WD"}
+	VehicleStatuses = []string{StatusActive, StatusInactive, StatusSold, StatusDiscontinued}
+)
+
+// ImportRow is one parsed vehicle with the 1-based line it starts on in the source file.
+// Rows with Errors are reported but never written.
+type ImportRow struct {
+	Line    int
+	Vehicle Vehicle
+	Errors  []ImportError
+}
+
+// ImportError is a problem with one row; Field is empty when it concerns the whole row.
+type ImportError struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+// ImportResult summarises an import. When Errors is non-empty nothing was written.
+type ImportResult struct {
+	DryRun    bool
+	Total     int
+	Created   int
+	Updated   int
+	Unchanged int
+	Errors    []ImportError
+}
+
+// importRecord is the raw shape of a row before validation; keys follow the GraphQL VehicleInput.
+type importRecord struct {
+	VIN          string `json:"vin"`
+	Name         string `json:"name"`
+	ModelCode    string `json:"modelCode"`
+	TractionType string `json:"tractionType"`
+	ReleaseYear  any    `json:"releaseYear"`
+	BatchNumber  string `json:"batchNumber"`
+	Color        string `json:"color"`
+	Mileage      any    `json:"mileage"`
+	Status       string `json:"status"`
+	Manufacturer string `json:"manufacturer"`
+	PlantCode    string `json:"plantCode"`
+}
+
+// toRow applies the VehicleInput shape: required fields, enum values and defaults. A missing
+// releaseYear stays 0 for ImportVehicles to fill from the VIN, as createVehicle does, before it
+// checks the business rules in VehicleRules.
+func (rec importRecord) toRow(line int) ImportRow {
+	var errs []ImportError
+	fail := func(field, msg string) { errs = append(errs, ImportError{Line: line, Field: field, Message: msg}) }
+	required := func(field, val string) string {
+		val = strings.TrimSpace(val)
+		if val == "" {
+			fail(field, "is required")
+		}
+		return val
+	}
+	integer := func(field string, val any, def *int) int {
+		if str, ok := val.(string); ok {
+			if val = strings.TrimSpace(str); val == "" {
+				val = nil
+			}
+		}
+		switch n := val.(type) {
+		case nil:
+			if def != nil {
+				return *def
+			}
+			fail(field, "is required")
+		case float64:
+			if n == math.Trunc(n) {
+				return int(n)
+			}
+			fail(field, "must be an integer")
+		case string:
+			if i, err := strconv.Atoi(n); err == nil {
+				return i
+			}
+			fail(field, "must be an integer")
+		default:
+			fail(field, "must be an integer")
+		}
+		return 0
+	}
+
+	zero := 0
+	v := Vehicle{
+		VIN:          NormalizeVIN(required("vin", rec.VIN)),
+		Name:         required("name", rec.Name),
+		ModelCode:    required("modelCode", rec.ModelCode),
+		BatchNumber:  required("batchNumber", rec.BatchNumber),
+		Color:        strings.TrimSpace(rec.Color),
+		ReleaseYear:  integer("releaseYear", rec.ReleaseYear, &zero),
+		Mileage:      integer("mileage", rec.Mileage, &zero),
+		Manufacturer: strings.TrimSpace(rec.Manufacturer),
+		PlantCode:    strings.TrimSpace(rec.PlantCode),
+	}
+
+	v.TractionType = strings.ToUpper(required("tractionType", rec.TractionType))
+	if v.TractionType == "4WD" {
+		v.TractionType = "FOUR_WD"
+	}
+	if v.TractionType != "" && !slices.Contains(TractionTypes, v.TractionType) {
+		fail("tractionType", "must be one of "+strings.Join(TractionTypes, ", "))
+	}
+
+	// Left empty when missing: new vehicles start ACTIVE and existing ones keep their status.
+	v.Status = strings.ToUpper(strings.TrimSpace(rec.Status))
+	if v.Status != "" && !slices.Contains(VehicleStatuses, v.Status) {
+		fail("status", "must be one of "+strings.Join(VehicleStatuses, ", "))
+	}
+	return ImportRow{Line: line, Vehicle: v, Errors: errs}
+}
+
+// csvColumns maps normalised header names (lowercase, no separators) to record fields.
+var csvColumns = map[string]func(*importRecord, string){
+	"vin":          func(r *importRecord, s string) { r.VIN = s },
+	"name":         func(r *importRecord, s string) { r.Name = s },
+	"modelcode":    func(r *importRecord, s string) { r.ModelCode = s },
+	"tractiontype": func(r *importRecord, s string) { r.TractionType = s },
+	"releaseyear":  func(r *importRecord, s string) { r.ReleaseYear = s },
+	"batchnumber":  func(r *importRecord, s string) { r.BatchNumber = s },
+	"color":        func(r *importRecord, s string) { r.Color = s },
+	"mileage":      func(r *importRecord, s string) { r.Mileage = s },
+	"status":       func(r *importRecord, s string) { r.Status = s },
+	"manufacturer": func(r *importRecord, s string) { r.Manufacturer = s },
+	"plantcode":    func(r *importRecord, s string) { r.PlantCode = s },
+}
+
+func normalizeColumn(h string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(h)))
+}
+
+// ParseVehiclesCSV reads a CSV file whose first line is a header naming VehicleInput fields
+// (camelCase or snake_case). Unknown columns are rejected so typos don't silently drop data.
+// The returned errors concern the file as a whole; row problems are attached to each row.
+func ParseVehiclesCSV(src io.Reader) ([]ImportRow, []ImportError) {
+	rd := csv.NewReader(src)
+	rd.TrimLeadingSpace = true
+	header, err := rd.Read()
+	if err != nil {
+		return nil, []ImportError{{Line: 1, Message: "cannot read header: " + err.Error()}}
+	}
+	setters := make([]func(*importRecord, string), len(header))
+	var errs []ImportError
+	for i, h := range header {
+		set, ok := csvColumns[normalizeColumn(h)]
+		if !ok {
+			errs = append(errs, ImportError{Line: 1, Field: h, Message: "unknown column"})
+			continue
+		}
+		setters[i] = set
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var rows []ImportRow
+	for {
+		fields, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if !errors.As(err, &pe) {
+				return rows, []ImportError{{Message: err.Error()}}
+			}
+			if pe.Err != csv.ErrFieldCount {
+				return rows, []ImportError{{Line: pe.Line, Message: pe.Err.Error()}}
+			}
+			rows = append(rows, ImportRow{Line: pe.StartLine, Errors: []ImportError{{Line: pe.StartLine, Message: pe.Err.Error()}}})
+			continue
+		}
+		line, _ := rd.FieldPos(0)
+		var rec importRecord
+		for i, f := range fields {
+			setters[i](&rec, f)
+		}
+		rows = append(rows, rec.toRow(line))
+	}
+	return rows, nil
+}
+
+// ParseVehiclesJSON reads a JSON array of VehicleInput-shaped objects. Errors are as for ParseVehiclesCSV.
+func ParseVehiclesJSON(src io.Reader) ([]ImportRow, []ImportError) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, []ImportError{{Line: 1, Message: err.Error()}}
+	}
+	lineAt := func(offset int64) int {
+		rest := data[offset:]
+		offset += int64(len(rest) - len(bytes.TrimLeft(rest, " \t\r\n,")))
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, []ImportError{{Line: 1, Message: "expected a JSON array of vehicles"}}
+	}
+	var rows []ImportRow
+	for dec.More() {
+		line := lineAt(dec.InputOffset())
+		var rec importRecord
+		if err := dec.Decode(&rec); err != nil {
+			var te *json.UnmarshalTypeError
+			if errors.As(err, &te) {
+				rows = append(rows, ImportRow{Line: line, Errors: []ImportError{{Line: line, Field: te.Field, Message: "has the wrong type"}}})
+				continue
+			}
+			return rows, []ImportError{{Line: line, Message: err.Error()}}
+		}
+		rows = append(rows, rec.toRow(line))
+	}
+	return rows, nil
+}
+
+var errImportAborted = errors.New("import aborted")
+
+// ImportVehicles creates (and with upsert, updates) the given vehicles in a single transaction,
+// auditing each write as actorID. Rows are matched on VIN and validated like single creates and
+// updates. If any row fails, or dryRun is set, nothing is written and the result describes what
+// would have happened.
+func (r *Repos) ImportVehicles(ctx context.Context, rows []ImportRow, upsert, dryRun bool, actorID int64) (*ImportResult, error) {
+	res := &ImportResult{DryRun: dryRun, Total: len(rows)}
+	err := r.InTx(ctx, func(tx *Repos) error {
+		vins := make([]string, 0, len(rows))
+		for _, row := range rows {
+			if row.Vehicle.VIN != "" {
+				vins = append(vins, row.Vehicle.VIN)
+			}
+		}
+		var existing []*Vehicle
+		if len(vins) > 0 {
+			err := tx.DB.Model(&existing).Where("vin IN (?)", pg.In(vins)).AllWithDeleted().For("UPDATE").Select()
+			if err != nil {
+				return err
+			}
+		}
+		byVIN := make(map[string]*Vehicle, len(existing))
+		for _, v := range existing {
+			byVIN[v.VIN] = v
+		}
+
+		type write struct {
+			v      *Vehicle
+			before *Vehicle // set for updates
+		}
+		var writes []write
+		seen := map[string]int{}
+		rules, now := tx.vehicleRules(), time.Now()
+		invalid := func(line int, errs []apperr.FieldError) bool {
+			for _, e := range errs {
+				res.Errors = append(res.Errors, ImportError{Line: line, Field: strings.Join(e.Path, "."), Message: e.Message})
+			}
+			return len(errs) > 0
+		}
+		for i := range rows {
+			row := &rows[i]
+			if len(row.Errors) > 0 {
+				res.Errors = append(res.Errors, row.Errors...)
+				continue
+			}
+			if first, dup := seen[row.Vehicle.VIN]; dup {
+				res.Errors = append(res.Errors, ImportError{
+					Line: row.Line, Field: "vin", Message: fmt.Sprintf("duplicates line %d", first),
+				})
+				continue
+			}
+			seen[row.Vehicle.VIN] = row.Line
+
+			cur, found := byVIN[row.Vehicle.VIN]
+			switch {
+			case !found:
+				if row.Vehicle.Status != "" && row.Vehicle.Status != StatusActive {
+					res.Errors = append(res.Errors, ImportError{
+						Line: row.Line, Field: "status",
+						Message: "new vehicles start ACTIVE; status changes only through movements",
+					})
+					continue
+				}
+				row.Vehicle.Status = StatusActive
+				if err := fillFromVIN(&row.Vehicle, now); err != nil {
+					var ae *apperr.Error
+					if !errors.As(err, &ae) {
+						return err
+					}
+					invalid(row.Line, ae.Fields)
+					continue
+				}
+				if invalid(row.Line, rules.ValidateVehicle(&row.Vehicle, nil, now)) {
+					continue
+				}
+				res.Created++
+				writes = append(writes, write{v: &row.Vehicle})
+			case cur.DeletedAt != nil:
+				res.Errors = append(res.Errors, ImportError{
+					Line: row.Line, Field: "vin", Message: "belongs to a deleted vehicle; restore it first",
+				})
+			case !upsert:
+				res.Errors = append(res.Errors, ImportError{Line: row.Line, Field: "vin", Message: "already exists"})
+			case row.Vehicle.Status != "" && row.Vehicle.Status != cur.Status:
+				res.Errors = append(res.Errors, ImportError{
+					Line: row.Line, Field: "status",
+					Message: fmt.Sprintf("differs from the current %s; status changes only through movements", cur.Status),
+				})
+			default:
+				next := *cur
+				next.Name, next.ModelCode, next.TractionType = row.Vehicle.Name, row.Vehicle.ModelCode, row.Vehicle.TractionType
+				next.BatchNumber, next.Color, next.Mileage = row.Vehicle.BatchNumber, row.Vehicle.Color, row.Vehicle.Mileage
+				// Like updateVehicle, leave the fields createVehicle may decode from the VIN alone when empty.
+				if row.Vehicle.ReleaseYear != 0 {
+					next.ReleaseYear = row.Vehicle.ReleaseYear
+				}
+				if row.Vehicle.Manufacturer != "" {
+					next.Manufacturer = row.Vehicle.Manufacturer
+				}
+				if row.Vehicle.PlantCode != "" {
+					next.PlantCode = row.Vehicle.PlantCode
+				}
+				if len(diffVehicles(cur, &next)) == 0 {
+					res.Unchanged++
+					continue
+				}
+				if invalid(row.Line, rules.ValidateVehicle(&next, cur, now)) {
+					continue
+				}
+				res.Updated++
+				writes = append(writes, write{v: &next, before: cur})
+			}
+		}
+		if dryRun || len(res.Errors) > 0 {
+			return errImportAborted
+		}
+
+		for _, w := range writes {
+			var err error
+			if w.before != nil {
+				err = tx.saveVehicle(ctx, w.before, w.v, actorID)
+			} else {
+				_, err = tx.CreateVehicle(ctx, w.v, actorID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportAborted) {
+		return nil, err
+	}
+	return res, nil
+}
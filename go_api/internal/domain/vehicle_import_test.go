@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// importSummary flattens parsed rows and file errors for comparison.
+func importSummary(rows []ImportRow, errs []ImportError) []string {
+	var out []string
+	for _, r := range rows {
+		out = append(out, fmt.Sprintf("row %d %s", r.Line, r.Vehicle.VIN))
+		for _, e := range r.Errors {
+			out = append(out, fmt.Sprintf("  line %d %s: %s", e.Line, e.Field, e.Message))
+		}
+	}
+	for _, e := range errs {
+		out = append(out, fmt.Sprintf("file line %d %s: %s", e.Line, e.Field, e.Message))
+	}
+	return out
+}
+
+func TestParseVehiclesCSV(t *testing.T) {
+	const header = "vin,name,model_code,Traction Type,batchNumber\n"
+	tests := []struct {
+		name string
+		csv  string
+		want []string
+	}{
+		{
+			name: "one row per line",
+			csv:  header + "1HGCM82633A004352,Civic,CV1,fwd,B-1\n1M8GDM9AXKP042788,Coach,CH2,4wd,B-2\n",
+			want: []string{"row 2 1HGCM82633A004352", "row 3 1M8GDM9AXKP042788"},
+		},
+		{
+			name: "quoted field spanning lines",
+			csv:  header + "1HGCM82633A004352,\"Civic\nhatchback\",CV1,FWD,B-1\n1M8GDM9AXKP042788,Coach,CH2,RWD,B-2\n",
+			want: []string{"row 2 1HGCM82633A004352", "row 4 1M8GDM9AXKP042788"},
+		},
+		{
+			name: "unknown columns",
+			csv:  "vin,nmae,colour\n1HGCM82633A004352,Civic,red\n",
+			want: []string{"file line 1 nmae: unknown column", "file line 1 colour: unknown column"},
+		},
+		{
+			name: "empty file",
+			csv:  "",
+			want: []string{"file line 1 : cannot read header: EOF"},
+		},
+		{
+			name: "wrong field count",
+			csv:  header + "1HGCM82633A004352,Civic\n1M8GDM9AXKP042788,Coach,CH2,RWD,B-2\n",
+			want: []string{
+				"row 2 ", "  line 2 : wrong number of fields",
+				"row 3 1M8GDM9AXKP042788",
+			},
+		},
+		{
+			name: "malformed quotes",
+			csv:  header + "1HGCM82633A004352,Civic,CV1,FWD,B-1\n1M8GDM9AXKP042788,Co\"ach,CH2,RWD,B-2\n",
+			want: []string{"row 2 1HGCM82633A004352", "file line 3 : bare \" in non-quoted-field"},
+		},
+		{
+			name: "row errors",
+			csv:  header + " 1hgcm82633a004352 ,,CV1,TRACKS,B-1\n",
+			want: []string{
+				"row 2 1HGCM82633A004352",
+				"  line 2 name: is required",
+				"  line 2 tractionType: must be one of RWD, FWD, AWD, FOUR_WD",
+			},
+		},
+		{
+			name: "row errors after a multi-line field",
+			csv:  header + "1HGCM82633A004352,\"Civic\n\nhatchback\",CV1,FWD,B-1\n1M8GDM9AXKP042788,Coach,,RWD,B-2\n",
+			want: []string{"row 2 1HGCM82633A004352", "row 5 1M8GDM9AXKP042788", "  line 5 modelCode: is required"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := importSummary(ParseVehiclesCSV(strings.NewReader(tt.csv)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseVehiclesJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "line of each object",
+			json: `[
+  {"vin": "1HGCM82633A004352", "name": "Civic", "modelCode": "CV1", "tractionType": "FWD", "batchNumber": "B-1"},
+
+  {
+    "vin": "1M8GDM9AXKP042788", "name": "Coach", "modelCode": "CH2", "tractionType": "RWD", "batchNumber": "B-2",
+    "releaseYear": 1.5
+  }
+]`,
+			want: []string{
+				"row 2 1HGCM82633A004352",
+				"row 4 1M8GDM9AXKP042788", "  line 4 releaseYear: must be an integer",
+			},
+		},
+		{
+			name: "wrong type",
+			json: "[\n{\"vin\": 7}\n]",
+			want: []string{"row 2 ", "  line 2 vin: has the wrong type"},
+		},
+		{
+			name: "not an array",
+			json: `{"vin": "1HGCM82633A004352"}`,
+			want: []string{"file line 1 : expected a JSON array of vehicles"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := importSummary(ParseVehiclesJSON(strings.NewReader(tt.json)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestImportRecordToRow(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  importRecord
+		want Vehicle
+	}{
+		{
+			name: "defaults",
+			rec:  importRecord{VIN: "1HGCM82633A004352", Name: "Civic", ModelCode: "CV1", TractionType: "4wd", BatchNumber: "B-1"},
+			want: Vehicle{VIN: "1HGCM82633A004352", Name: "Civic", ModelCode: "CV1", TractionType: "FOUR_WD", BatchNumber: "B-1"},
+		},
+		{
+			name: "every field",
+			rec: importRecord{
+				VIN: "1HGCM82633A004352", Name: " Civic ", ModelCode: "CV1", TractionType: "awd", BatchNumber: "B-1",
+				Color: " red ", ReleaseYear: "2003", Mileage: float64(1200), Status: "sold", Manufacturer: " Honda ", PlantCode: "A",
+			},
+			want: Vehicle{
+				VIN: "1HGCM82633A004352", Name: "Civic", ModelCode: "CV1", TractionType: "AWD", BatchNumber: "B-1",
+				Color: "red", ReleaseYear: 2003, Mileage: 1200, Status: StatusSold, Manufacturer: "Honda", PlantCode: "A",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := tt.rec.toRow(7)
+			if len(row.Errors) > 0 {
+				t.Fatalf("unexpected errors: %+v", row.Errors)
+			}
+			if row.Line != 7 || !reflect.DeepEqual(row.Vehicle, tt.want) {
+				t.Errorf("toRow = line %d %+v, want line 7 %+v", row.Line, row.Vehicle, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "strings"
+
+// vinValues transliterates VIN characters for the check digit. I, O and Q never appear in a VIN.
+var vinValues = map[byte]int{
+	'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+	'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+	'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+	'0': 0, '1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+}
+
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// NormalizeVIN upper-cases vin and strips surrounding whitespace.
+func NormalizeVIN(vin string) string {
+	return strings.ToUpper(strings.TrimSpace(vin))
+}
+
+// VINCheckDigit computes the check digit (position 9) of a 17-character VIN as defined for
+// ISO 3779 VINs in North America: '0'-'9' or 'X'. ok is false if vin has the wrong length or
+// contains characters a VIN cannot.
+func VINCheckDigit(vin string) (digit byte, ok bool) {
+	if len(vin) != 17 {
+		return 0, false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		v, valid := vinValues[vin[i]]
+		if !valid {
+			return 0, false
+		}
+		sum += v * vinWeights[i]
+	}
+	r := sum % 11
+	if r == 10 {
+		return 'X', true
+	}
+	return byte('0' + r), true
+}
+
+// vinProblem describes what is wrong with vin, or returns "" if it is valid. The check digit is
+// only verified when checkDigit is set, since VINs outside North America often don't carry one.
+func vinProblem(vin string, checkDigit bool) string {
+	if len(vin) != 17 {
+		return "must be 17 characters"
+	}
+	for i := 0; i < len(vin); i++ {
+		if _, ok := vinValues[vin[i]]; !ok {
+			return "may only contain digits and letters other than I, O and Q"
+		}
+	}
+	if checkDigit {
+		if want, _ := VINCheckDigit(vin); vin[8] != want {
+			return "has an invalid check digit (position 9 should be " + string(want) + ")"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+// VINInfo is what can be read from a VIN without any external service.
+type VINInfo struct {
+	VIN             string
+	WMI             string // world manufacturer identifier, positions 1-3
+	Manufacturer    string // empty when the WMI is not in the embedded table
+	Country         string
+	Region          string
+	CheckDigitValid bool
+	// ModelYear is the most likely model year from position 10, or 0 when it has none.
+	// The code repeats every 30 years; ModelYearCandidates lists every plausible reading.
+	ModelYear           int
+	ModelYearCandidates []int
+	PlantCode           string // position 11; its meaning is manufacturer-specific
+	SerialNumber        string // positions 12-17
+}
+
+var (
+	//go:embed vindata/wmi.csv
+	wmiCSV []byte
+	//go:embed vindata/countries.csv
+	countriesCSV []byte
+
+	vinTablesOnce sync.Once
+	wmiTable      map[string]string
+	countryRanges []countryRange
+)
+
+type countryRange struct{ from, to, country string }
+
+// vinOrder is the ISO 3780 ordering of the second WMI character used by country ranges.
+const vinOrder = "ABCDEFGHJKLMNPRSTUVWXYZ1234567890"
+
+func loadVINTables() {
+	read := func(data []byte) [][]string {
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			panic("vindata: " + err.Error()) // embedded at build time; cannot fail at runtime
+		}
+		return rows[1:]
+	}
+	wmiTable = map[string]string{}
+	for _, r := range read(wmiCSV) {
+		wmiTable[r[0]] = r[1]
+	}
+	for _, r := range read(countriesCSV) {
+		countryRanges = append(countryRanges, countryRange{from: r[0], to: r[1], country: r[2]})
+	}
+}
+
+func vinRegion(c byte) string {
+	switch {
+	case c >= 'A' && c <= 'H':
+		return "Africa"
+	case c >= 'J' && c <= 'R':
+		return "Asia"
+	case c >= 'S' && c <= 'Z':
+		return "Europe"
+	case c >= '1' && c <= '5':
+		return "North America"
+	case c == '6' || c == '7':
+		return "Oceania"
+	case c == '8' || c == '9':
+		return "South America"
+	}
+	return ""
+}
+
+func vinCountry(wmi string) string {
+	pos := strings.IndexByte(vinOrder, wmi[1])
+	for _, r := range countryRanges {
+		if r.from[0] == wmi[0] && pos >= strings.IndexByte(vinOrder, r.from[1]) && pos <= strings.IndexByte(vinOrder, r.to[1]) {
+			return r.country
+		}
+	}
+	return ""
+}
+
+// vinYearCodes maps position 10 to the first model year it stands for (1980-2009); the same
+// code is reused 30 years later.
+const vinYearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// modelYears returns the years code may stand for, at most maxYear, most likely first. North
+// American passenger VINs put a letter at position 7 for 2010 onwards and a digit before that.
+func modelYears(vin string, maxYear int) []int {
+	i := strings.IndexByte(vinYearCodes, vin[9])
+	if i < 0 {
+		return nil
+	}
+	early, late := 1980+i, 2010+i
+	var out []int
+	if late <= maxYear {
+		out = append(out, late)
+	}
+	out = append(out, early)
+	if len(out) == 2 && vin[6] >= '0' && vin[6] <= '9' {
+		out[0], out[1] = out[1], out[0]
+	}
+	return out
+}
+
+// DecodeVIN reads the manufacturer, origin, model year, plant and serial number from vin using
+// the embedded tables. It fails with a validation error only if vin is not a well-formed VIN;
+// parts it cannot identify are left empty.
+func DecodeVIN(vin string, now time.Time) (*VINInfo, error) {
+	vin = NormalizeVIN(vin)
+	if p := vinProblem(vin, false); p != "" {
+		return nil, apperr.Invalid("vin", "%s", p)
+	}
+	vinTablesOnce.Do(loadVINTables)
+
+	info := &VINInfo{
+		VIN: vin, WMI: vin[:3], Region: vinRegion(vin[0]), Country: vinCountry(vin[:3]),
+		PlantCode: vin[10:11], SerialNumber: vin[11:],
+	}
+	want, _ := VINCheckDigit(vin)
+	info.CheckDigitValid = vin[8] == want
+	// Makers of fewer than 1000 vehicles a year share a WMI ending in 9 and are told apart by
+	// positions 12-14.
+	if vin[2] == '9' {
+		info.Manufacturer = wmiTable[vin[:3]+vin[11:14]]
+	}
+	if info.Manufacturer == "" {
+		info.Manufacturer = wmiTable[info.WMI]
+	}
+	info.ModelYearCandidates = modelYears(vin, now.Year()+1)
+	if len(info.ModelYearCandidates) > 0 {
+		info.ModelYear = info.ModelYearCandidates[0]
+	}
+	return info, nil
+}
+
+// ReleaseYearMismatch reports whether year contradicts the model year encoded in vin. Only North
+// American VINs are checked: like the check digit, the model-year code at position 10 is mandatory
+// there, while elsewhere manufacturers may put anything in it. VINs that don't decode never mismatch.
+func ReleaseYearMismatch(vin string, year int, now time.Time) bool {
+	info, err := DecodeVIN(vin, now)
+	if err != nil || info.Region != "North America" || len(info.ModelYearCandidates) == 0 {
+		return false
+	}
+	for _, y := range info.ModelYearCandidates {
+		if y == year {
+			return false
+		}
+	}
+	return true
+}
+
+// fillFromVIN fills in the manufacturer, plant code and release year of a new vehicle from its
+// VIN where they were left empty. A malformed VIN is left for ValidateVehicle to report.
+func fillFromVIN(v *Vehicle, now time.Time) error {
+	info, err := DecodeVIN(v.VIN, now)
+	if err != nil {
+		return nil
+	}
+	if v.Manufacturer == "" {
+		v.Manufacturer = info.Manufacturer
+	}
+	if v.PlantCode == "" {
+		v.PlantCode = info.PlantCode
+	}
+	if v.ReleaseYear == 0 {
+		if info.ModelYear == 0 {
+			return apperr.Invalid("releaseYear", "is required; it could not be decoded from the VIN")
+		}
+		v.ReleaseYear = info.ModelYear
+	}
+	return nil
+}
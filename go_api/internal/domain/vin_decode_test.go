@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+)
+
+var vinNow = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func TestDecodeVIN(t *testing.T) {
+	tests := []struct {
+		name string
+		vin  string
+		want VINInfo
+	}{
+		{
+			name: "north american",
+			vin:  " 1hgcm82633a004352 ",
+			want: VINInfo{
+				VIN: "1HGCM82633A004352", WMI: "1HG", Manufacturer: "Honda", Country: "United States",
+				Region: "North America", CheckDigitValid: true, ModelYear: 2003, ModelYearCandidates: []int{2003},
+				PlantCode: "A", SerialNumber: "004352",
+			},
+		},
+		{
+			name: "letter at position 7 prefers the later year",
+			vin:  "1HGCM8A63EA004352",
+			want: VINInfo{
+				VIN: "1HGCM8A63EA004352", WMI: "1HG", Manufacturer: "Honda", Country: "United States",
+				Region: "North America", ModelYear: 2014, ModelYearCandidates: []int{2014, 1984},
+				PlantCode: "A", SerialNumber: "004352",
+			},
+		},
+		{
+			name: "digit at position 7 prefers the earlier year",
+			vin:  "1HGCM8263EA004352",
+			want: VINInfo{
+				VIN: "1HGCM8263EA004352", WMI: "1HG", Manufacturer: "Honda", Country: "United States",
+				Region: "North America", ModelYear: 1984, ModelYearCandidates: []int{1984, 2014},
+				PlantCode: "A", SerialNumber: "004352",
+			},
+		},
+		{
+			name: "european without model year",
+			vin:  "WVWZZZ1JZ0W000001",
+			want: VINInfo{
+				VIN: "WVWZZZ1JZ0W000001", WMI: "WVW", Manufacturer: "Volkswagen", Country: "Germany",
+				Region: "Europe", PlantCode: "W", SerialNumber: "000001",
+			},
+		},
+		{
+			name: "unknown manufacturer",
+			vin:  "9ZZZZZZZZ1Z000001",
+			want: VINInfo{
+				VIN: "9ZZZZZZZZ1Z000001", WMI: "9ZZ", Region: "South America",
+				ModelYear: 2001, ModelYearCandidates: []int{2001}, PlantCode: "Z", SerialNumber: "000001",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeVIN(tt.vin, vinNow)
+			if err != nil {
+				t.Fatalf("DecodeVIN(%q): %v", tt.vin, err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("DecodeVIN(%q) = %+v, want %+v", tt.vin, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeVINRejectsMalformed(t *testing.T) {
+	for _, vin := range []string{"", "1HGCM8263", "1HGCM82633AO04352"} {
+		if _, err := DecodeVIN(vin, vinNow); !errors.Is(err, apperr.ErrValidation) {
+			t.Errorf("DecodeVIN(%q) err = %v, want a validation error", vin, err)
+		}
+	}
+}
+
+func TestReleaseYearMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		vin  string
+		year int
+		want bool
+	}{
+		{"matching year", "1HGCM82633A004352", 2003, false},
+		{"other year", "1HGCM82633A004352", 2004, true},
+		{"either candidate", "1HGCM8A63EA004352", 1984, false},
+		{"outside north america", "WVWZZZ1JZYW000001", 1990, false},
+		{"malformed", "1HGCM8263", 1990, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReleaseYearMismatch(tt.vin, tt.year, vinNow); got != tt.want {
+				t.Errorf("ReleaseYearMismatch(%q, %d) = %v, want %v", tt.vin, tt.year, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillFromVIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Vehicle
+		want    Vehicle
+		wantErr bool
+	}{
+		{
+			name: "fills empty fields",
+			in:   Vehicle{VIN: "1HGCM82633A004352"},
+			want: Vehicle{VIN: "1HGCM82633A004352", Manufacturer: "Honda", PlantCode: "A", ReleaseYear: 2003},
+		},
+		{
+			name: "keeps given fields",
+			in:   Vehicle{VIN: "1HGCM82633A004352", Manufacturer: "Acura", PlantCode: "B", ReleaseYear: 2004},
+			want: Vehicle{VIN: "1HGCM82633A004352", Manufacturer: "Acura", PlantCode: "B", ReleaseYear: 2004},
+		},
+		{
+			name:    "undecodable year",
+			in:      Vehicle{VIN: "WVWZZZ1JZ0W000001"},
+			wantErr: true,
+		},
+		{
+			name: "undecodable year given",
+			in:   Vehicle{VIN: "WVWZZZ1JZ0W000001", ReleaseYear: 1999},
+			want: Vehicle{VIN: "WVWZZZ1JZ0W000001", Manufacturer: "Volkswagen", PlantCode: "W", ReleaseYear: 1999},
+		},
+		{
+			name: "malformed VIN left alone",
+			in:   Vehicle{VIN: "bad"},
+			want: Vehicle{VIN: "bad"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.in
+			err := fillFromVIN(&v, vinNow)
+			if tt.wantErr {
+				if !errors.Is(err, apperr.ErrValidation) {
+					t.Fatalf("err = %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.Manufacturer != tt.want.Manufacturer || v.PlantCode != tt.want.PlantCode || v.ReleaseYear != tt.want.ReleaseYear {
+				t.Errorf("got manufacturer %q, plant %q, year %d; want %q, %q, %d",
+					v.Manufacturer, v.PlantCode, v.ReleaseYear, tt.want.Manufacturer, tt.want.PlantCode, tt.want.ReleaseYear)
+			}
+		})
+	}
+}
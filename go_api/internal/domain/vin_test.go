@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestVINCheckDigit(t *testing.T) {
+	tests := []struct {
+		vin    string
+		want   byte
+		wantOK bool
+	}{
+		{"1HGCM82633A004352", '3', true},
+		{"1M8GDM9AXKP042788", 'X', true},
+		{"11111111111111111", '1', true},
+		{"1HGCM82633A00435", 0, false},
+		{"1HGCM82633A0043521", 0, false},
+		{"1HGCM82633A00435I", 0, false},
+		{"1hgcm82633a004352", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vin, func(t *testing.T) {
+			got, ok := VINCheckDigit(tt.vin)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("VINCheckDigit = %q, %v; want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestVINProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		vin        string
+		checkDigit bool
+		want       string
+	}{
+		{"valid", "1HGCM82633A004352", true, ""},
+		{"too short", "1HGCM8263", false, "must be 17 characters"},
+		{"letter O", "1HGCM82633AO04352", false, "may only contain digits and letters other than I, O and Q"},
+		{"bad check digit", "1HGCM82643A004352", true, "has an invalid check digit (position 9 should be 3)"},
+		{"bad check digit not checked", "1HGCM82643A004352", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := vinProblem(tt.vin, tt.checkDigit); got != tt.want {
+				t.Errorf("vinProblem(%q) = %q, want %q", tt.vin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeVIN(t *testing.T) {
+	if got, want := NormalizeVIN("  1hgcm82633a004352\n"), "1HGCM82633A004352"; got != want {
+		t.Errorf("NormalizeVIN = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,365 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// Webhook event types: EventVehicleUpdated, the correctionEvents, and one per movement type, named
+// by movementEvent, so a subscriber can ask for sales and defects only.
+const EventVehicleUpdated = "VEHICLE_UPDATED"
+
+// correctionEvents are published under their event log names. A reversal is published only as
+// MOVEMENT_REVERSED, not as a new movement of the reversed type.
+var correctionEvents = []string{EventMovementUpdated, EventMovementVoided, EventMovementReversed}
+
+func movementEvent(moveType string) string { return "MOVEMENT_" + moveType }
+
+// Delivery statuses. A delivery stays PENDING while it has attempts left.
+const (
+	DeliveryPending   = "PENDING"
+	DeliverySucceeded = "SUCCEEDED"
+	DeliveryFailed    = "FAILED"
+)
+
+// Webhook is an outbound subscription. Secret signs every delivery.
+type Webhook struct {
+	tableName struct{}  `pg:"webhooks"`
+	ID        int64     `pg:"id,pk"`
+	URL       string    `pg:"url,notnull"`
+	Events    []string  `pg:"events,array,notnull"`
+	Secret    string    `pg:"secret,notnull"`
+	Active    bool      `pg:"active,notnull,use_zero"`
+	CreatedBy int64     `pg:"created_by"`
+	CreatedAt time.Time `pg:"created_at,default:now()"`
+	UpdatedAt time.Time `pg:"updated_at,default:now()"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook. Body is stored exactly as
+// signed so redeliveries are byte-for-byte identical.
+type WebhookDelivery struct {
+	tableName      struct{}   `pg:"webhook_deliveries"`
+	ID             int64      `pg:"id,pk"`
+	WebhookID      int64      `pg:"webhook_id,notnull"`
+	Webhook        *Webhook   `pg:"-"`
+	EventID        int64      `pg:"event_id,notnull"` // DomainEvent.Seq
+	EventType      string     `pg:"event_type,notnull"`
+	Body           string     `pg:"body,notnull"`
+	Status         string     `pg:"status,notnull,default:'PENDING'"`
+	Attempts       int        `pg:"attempts,notnull,use_zero"`
+	NextAttemptAt  time.Time  `pg:"next_attempt_at,default:now()"`
+	LastAttemptAt  *time.Time `pg:"last_attempt_at"`
+	ResponseStatus int        `pg:"response_status"` // 0 (NULL) when no response was received
+	LastError      string     `pg:"last_error"`
+	CreatedAt      time.Time  `pg:"created_at,default:now()"`
+}
+
+func deliveryCursor(d *WebhookDelivery) Cursor {
+	return timeCursor("created_at", true, d.CreatedAt, d.ID)
+}
+
+// validateWebhook checks w, including that each of its movement events names an existing type.
+func (r *Repos) validateWebhook(ctx context.Context, w *Webhook) error {
+	types, err := r.ListMovementTypes(ctx)
+	if err != nil {
+		return err
+	}
+	events := append([]string{EventVehicleUpdated}, correctionEvents...)
+	for _, t := range types {
+		events = append(events, movementEvent(t.Name))
+	}
+	var errs []apperr.FieldError
+	fail := func(path, msg string) {
+		errs = append(errs, apperr.FieldError{Path: []string{path}, Message: msg})
+	}
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("url", "must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		fail("events", "must not be empty")
+	}
+	for _, e := range w.Events {
+		if !slices.Contains(events, e) {
+			fail("events", "unknown event "+e)
+		}
+	}
+	if len(errs) > 0 {
+		return apperr.Validation(errs...)
+	}
+	return nil
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b) // never fails
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// CreateWebhook stores w, generating a secret unless one is given.
+func (r *Repos) CreateWebhook(ctx context.Context, w *Webhook) (*Webhook, error) {
+	if err := r.validateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	if w.Secret == "" {
+		w.Secret = newWebhookSecret()
+	}
+	_, err := r.DB.ModelContext(ctx, w).Insert()
+	return w, err
+}
+
+func (r *Repos) UpdateWebhook(ctx context.Context, w *Webhook) (*Webhook, error) {
+	if err := r.validateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	w.UpdatedAt = time.Now()
+	res, err := r.DB.ModelContext(ctx, w).WherePK().Update()
+	if err != nil {
+		return nil, err
+	}
+	if res.RowsAffected() == 0 {
+		return nil, apperr.NotFound("webhook with id %d not found", w.ID)
+	}
+	return w, nil
+}
+
+// DeleteWebhook removes the webhook together with its delivery log.
+func (r *Repos) DeleteWebhook(ctx context.Context, id int64) error {
+	res, err := r.DB.ModelContext(ctx, &Webhook{ID: id}).WherePK().Delete()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return apperr.NotFound("webhook with id %d not found", id)
+	}
+	return nil
+}
+
+func (r *Repos) GetWebhookByID(ctx context.Context, id int64) (*Webhook, error) {
+	var w Webhook
+	if err := r.DB.ModelContext(ctx, &w).Where("id = ?", id).Select(); err != nil {
+		return nil, notFound(err, "webhook with id %d not found", id)
+	}
+	return &w, nil
+}
+
+func (r *Repos) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	var ws []*Webhook
+	err := r.DB.ModelContext(ctx, &ws).Order("id ASC").Select()
+	return ws, err
+}
+
+// movementPayload describes m for webhooks, with the VIN and resulting status of its vehicle
+// when m.Vehicle is set.
+func movementPayload(m *Movement) map[string]any {
+	p := map[string]any{
+		"movement": map[string]any{
+			"id": m.ID, "vehicleId": m.VehicleID, "type": m.Type, "description": m.Description,
+			"occurredAt": m.OccurredAt, "metadata": m.Metadata, "createdBy": m.CreatedBy,
+			"createdAt": m.CreatedAt, "fromLocationId": optID(m.FromLocationID), "toLocationId": optID(m.ToLocationID),
+			"reversesId": optID(m.ReversesID), "correlationId": m.CorrelationID,
+		},
+	}
+	if m.Vehicle != nil {
+		p["vehicle"] = map[string]any{
+			"id": m.Vehicle.ID, "vin": m.Vehicle.VIN, "status": m.Vehicle.Status,
+			"currentLocationId": optID(m.Vehicle.CurrentLocationID),
+		}
+	}
+	return p
+}
+
+// vehiclePayload describes v, and the changes that led to it when there are any.
+func vehiclePayload(v *Vehicle, changes map[string]FieldChange) map[string]any {
+	fields := vehicleFields(v)
+	fields["id"] = v.ID
+	p := map[string]any{"vehicle": fields}
+	if changes != nil {
+		p["changes"] = changes
+	}
+	return p
+}
+
+// webhookEvent names the webhook event e is published as, or returns "" when webhooks don't carry it.
+func webhookEvent(e *DomainEvent) string {
+	switch e.Type {
+	case EventVehicleUpdated, EventMovementUpdated, EventMovementVoided, EventMovementReversed:
+		return e.Type
+	case EventMovementCreated:
+		m, _ := e.Payload["movement"].(map[string]any)
+		if m["reversesId"] != nil {
+			return ""
+		}
+		if t, ok := m["type"].(string); ok {
+			return movementEvent(t)
+		}
+	}
+	return ""
+}
+
+// DispatchEvents turns up to limit domain events past the dispatch cursor into deliveries, one per
+// active webhook subscribed to the event, advances the cursor and reports how many events it
+// handled. The cursor row is claimed with SKIP LOCKED, so while one worker dispatches, the others
+// skip straight to sending.
+func (r *Repos) DispatchEvents(ctx context.Context, limit int) (int, error) {
+	var n int
+	err := r.InTx(ctx, func(tx *Repos) error {
+		var lastXID, lastSeq int64
+		_, err := tx.DB.QueryOneContext(ctx, pg.Scan(&lastXID, &lastSeq),
+			"SELECT last_xid, last_seq FROM webhook_dispatch_cursor FOR UPDATE SKIP LOCKED")
+		if errors.Is(err, pg.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var events []*DomainEvent
+		err = settledEvents(tx.DB.ModelContext(ctx, &events), lastXID, lastSeq).Limit(limit).Select()
+		if err != nil || len(events) == 0 {
+			return err
+		}
+		var hooks []*Webhook
+		if err := tx.DB.ModelContext(ctx, &hooks).Where("active").Select(); err != nil {
+			return err
+		}
+		var deliveries []*WebhookDelivery
+		for _, e := range events {
+			typ := webhookEvent(e)
+			if typ == "" {
+				continue
+			}
+			body, err := json.Marshal(map[string]any{
+				"id": e.Seq, "type": typ, "createdAt": e.CreatedAt, "data": e.Payload,
+			})
+			if err != nil {
+				return err
+			}
+			for _, h := range hooks {
+				if slices.Contains(h.Events, typ) {
+					deliveries = append(deliveries, &WebhookDelivery{
+						WebhookID: h.ID, EventID: e.Seq, EventType: typ, Body: string(body),
+					})
+				}
+			}
+		}
+		if len(deliveries) > 0 {
+			if _, err := tx.DB.ModelContext(ctx, &deliveries).Insert(); err != nil {
+				return err
+			}
+		}
+		last := events[len(events)-1]
+		_, err = tx.DB.ExecContext(ctx, "UPDATE webhook_dispatch_cursor SET last_xid = ?::xid8, last_seq = ?",
+			strconv.FormatInt(last.XID, 10), last.Seq)
+		n = len(events)
+		return err
+	})
+	return n, err
+}
+
+// ClaimDeliveries returns up to limit pending deliveries that are due, with their webhooks
+// loaded, and pushes their next attempt lease into the future so no other worker picks them up
+// meanwhile. If the claiming worker dies, they become due again once the lease runs out.
+func (r *Repos) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*WebhookDelivery, error) {
+	var ds []*WebhookDelivery
+	err := r.InTx(ctx, func(tx *Repos) error {
+		err := tx.DB.ModelContext(ctx, &ds).
+			Where("status = ?", DeliveryPending).Where("next_attempt_at <= now()").
+			Order("next_attempt_at ASC").Limit(limit).For("UPDATE SKIP LOCKED").Select()
+		if err != nil || len(ds) == 0 {
+			return err
+		}
+		ids := make([]int64, 0, len(ds))
+		hookIDs := make([]int64, 0, len(ds))
+		for _, d := range ds {
+			ids = append(ids, d.ID)
+			hookIDs = append(hookIDs, d.WebhookID)
+		}
+		_, err = tx.DB.ModelContext(ctx, (*WebhookDelivery)(nil)).
+			Set("next_attempt_at = ?", time.Now().Add(lease)).Where("id IN (?)", pg.In(ids)).Update()
+		if err != nil {
+			return err
+		}
+		var hooks []*Webhook
+		if err := tx.DB.ModelContext(ctx, &hooks).Where("id IN (?)", pg.In(hookIDs)).Select(); err != nil {
+			return err
+		}
+		byID := make(map[int64]*Webhook, len(hooks))
+		for _, h := range hooks {
+			byID[h.ID] = h
+		}
+		for _, d := range ds {
+			d.Webhook = byID[d.WebhookID]
+		}
+		return nil
+	})
+	return ds, err
+}
+
+// SaveDeliveryAttempt stores the outcome of an attempt on d.
+func (r *Repos) SaveDeliveryAttempt(ctx context.Context, d *WebhookDelivery) error {
+	_, err := r.DB.ModelContext(ctx, d).
+		Column("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "last_error").
+		WherePK().Update()
+	return err
+}
+
+// RedeliverWebhook queues the delivery to be sent again right away, with a fresh set of attempts.
+func (r *Repos) RedeliverWebhook(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	err := r.InTx(ctx, func(tx *Repos) error {
+		if err := tx.DB.ModelContext(ctx, &d).Where("id = ?", id).For("UPDATE").Select(); err != nil {
+			return notFound(err, "webhook delivery with id %d not found", id)
+		}
+		d.Status, d.Attempts, d.NextAttemptAt = DeliveryPending, 0, time.Now()
+		_, err := tx.DB.ModelContext(ctx, &d).Column("status", "attempts", "next_attempt_at").WherePK().Update()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// DeliveryFilter narrows the delivery log. Nil or empty fields are ignored.
+type DeliveryFilter struct {
+	WebhookID *int64
+	Statuses  []string
+}
+
+func (f DeliveryFilter) Where(q *orm.Query) (*orm.Query, error) {
+	if f.WebhookID != nil {
+		q = q.Where("webhook_id = ?", *f.WebhookID)
+	}
+	if len(f.Statuses) > 0 {
+		q = q.Where("status IN (?)", pg.In(f.Statuses))
+	}
+	return q, nil
+}
+
+// ListWebhookDeliveries returns matching deliveries newest first, using (created_at, id) as the keyset.
+func (r *Repos) ListWebhookDeliveries(ctx context.Context, filter DeliveryFilter, page PageArgs) (*Page[*WebhookDelivery], error) {
+	var items []*WebhookDelivery
+	q := r.DB.ModelContext(ctx, &items).Apply(filter.Where)
+	total, err := page.total(q)
+	if err != nil {
+		return nil, err
+	}
+	if q, err = keyset(q, page.After, "created_at", true); err != nil {
+		return nil, err
+	}
+	limit := page.Limit()
+	if err = q.Order("created_at DESC", "id DESC").Limit(limit + 1).Select(); err != nil {
+		return nil, err
+	}
+	return newPage(items, limit, total, deliveryCursor), nil
+}
@@ -0,0 +1,95 @@
+// Package export streams vehicles and movements to HTTP clients as CSV, JSON Lines or XLSX.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Supported output formats, selected with the "format" query parameter.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatXLSX  = "xlsx"
+)
+
+var contentTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// RowWriter writes a table one row at a time. Values are strings, integers, times or nil.
+type RowWriter interface {
+	Header(cols []string) error
+	Row(vals []any) error
+	Close() error
+}
+
+func newRowWriter(format string, w io.Writer) RowWriter {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if x == nil {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+type csvWriter struct{ w *csv.Writer }
+
+func (c *csvWriter) Header(cols []string) error { return c.w.Write(cols) }
+
+func (c *csvWriter) Row(vals []any) error {
+	rec := make([]string, len(vals))
+	for i, v := range vals {
+		rec[i] = formatValue(v)
+	}
+	return c.w.Write(rec)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc  *json.Encoder
+	cols []string
+}
+
+func (j *jsonlWriter) Header(cols []string) error {
+	j.cols = cols
+	return nil
+}
+
+func (j *jsonlWriter) Row(vals []any) error {
+	obj := make(map[string]any, len(vals))
+	for i, v := range vals {
+		if t, ok := v.(*time.Time); ok && t == nil {
+			v = nil
+		}
+		obj[j.cols[i]] = v
+	}
+	return j.enc.Encode(obj)
+}
+
+func (j *jsonlWriter) Close() error { return nil }
@@ -0,0 +1,308 @@
+package export
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	httpx "github.com/Kenfoxfire/Gear-Core-app/internal/http"
+	"github.com/google/uuid"
+)
+
+// Handler serves the export endpoints. It expects httpx.AuthMiddleware to run first.
+type Handler struct {
+	Repos *domain.Repos
+	Authz *domain.Authorizer
+}
+
+// allow writes 401 or 403 and returns false unless the caller holds every perm.
+func (h *Handler) allow(w http.ResponseWriter, r *http.Request, perms ...string) bool {
+	_, role, ok := httpx.UserFrom(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	for _, p := range perms {
+		can, err := h.Authz.Can(r.Context(), role, p)
+		if err != nil {
+			log.Printf("export: check %s: %v", p, err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return false
+		}
+		if !can {
+			http.Error(w, httpx.ErrForbidden.Error(), http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+var vehicleColumns = []string{
+	"id", "vin", "name", "modelCode", "tractionType", "releaseYear", "batchNumber",
+	"color", "mileage", "status", "manufacturer", "plantCode", "currentLocationId", "createdAt", "updatedAt", "deletedAt",
+}
+
+var movementColumns = []string{
+	"id", "vehicleId", "type", "description", "occurredAt", "fromLocationId", "toLocationId",
+	"reversesId", "voidedAt", "voidReason", "correlationId", "createdBy", "createdAt",
+}
+
+// Vehicles streams vehicles. It accepts the fields of the GraphQL VehicleFilter as query
+// parameters (list fields comma separated or repeated), plus sort, direction, includeDeleted and format.
+func (h *Handler) Vehicles(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, domain.PermVehicleExport) {
+		return
+	}
+	q := r.URL.Query()
+	filter, err := parseVehicleFilter(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.IncludeDeleted && !h.allow(w, r, domain.PermVehicleReadDeleted) {
+		return
+	}
+	sort, err := parseVehicleSort(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rw, ok := start(w, q, "vehicles")
+	if !ok {
+		return
+	}
+	err = rw.Header(vehicleColumns)
+	if err == nil {
+		err = h.Repos.StreamVehicles(r.Context(), filter, sort, func(v *domain.Vehicle) error {
+			return rw.Row([]any{
+				v.ID, v.VIN, v.Name, v.ModelCode, v.TractionType, v.ReleaseYear, v.BatchNumber,
+				v.Color, v.Mileage, v.Status, v.Manufacturer, v.PlantCode, optID(v.CurrentLocationID),
+				v.CreatedAt, v.UpdatedAt, v.DeletedAt,
+			})
+		})
+	}
+	finish(rw, err, "vehicles")
+}
+
+// Movements streams movements. It accepts vehicleId, type (comma separated or repeated),
+// from and to (RFC 3339, on occurredAt), correlationId, includeDeleted and format. Movements of
+// deleted vehicles are only included on request.
+func (h *Handler) Movements(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, domain.PermVehicleExport, domain.PermMovementRead) {
+		return
+	}
+	q := r.URL.Query()
+	filter, err := parseMovementFilter(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.IncludeDeleted && !h.allow(w, r, domain.PermVehicleReadDeleted) {
+		return
+	}
+	rw, ok := start(w, q, "movements")
+	if !ok {
+		return
+	}
+	err = rw.Header(movementColumns)
+	if err == nil {
+		err = h.Repos.StreamMovements(r.Context(), filter, func(m *domain.Movement) error {
+			return rw.Row([]any{
+				m.ID, m.VehicleID, m.Type, m.Description, m.OccurredAt,
+				optID(m.FromLocationID), optID(m.ToLocationID), optID(m.ReversesID), m.VoidedAt, m.VoidReason, m.CorrelationID,
+				m.CreatedBy, m.CreatedAt,
+			})
+		})
+	}
+	finish(rw, err, "movements")
+}
+
+// start validates the format and writes the response headers.
+func start(w http.ResponseWriter, q url.Values, name string) (RowWriter, bool) {
+	format := strings.ToLower(q.Get("format"))
+	if format == "" {
+		format = FormatCSV
+	}
+	ct, ok := contentTypes[format]
+	if !ok {
+		http.Error(w, "format must be one of csv, jsonl, xlsx", http.StatusBadRequest)
+		return nil, false
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`,
+		name, time.Now().UTC().Format("20060102-150405"), format))
+	return newRowWriter(format, w), true
+}
+
+// finish closes the writer. Once streaming has begun the status code is already sent,
+// so a failure can only be logged and the response cut short.
+func finish(rw RowWriter, err error, name string) {
+	if cerr := rw.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		log.Printf("export %s: %v", name, err)
+	}
+}
+
+func list(q url.Values, key string) []string {
+	var out []string
+	for _, v := range q[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, strings.ToUpper(s))
+			}
+		}
+	}
+	return out
+}
+
+func optString(q url.Values, key string) *string {
+	if !q.Has(key) {
+		return nil
+	}
+	s := q.Get(key)
+	return &s
+}
+
+func optInt(q url.Values, key string) (*int, error) {
+	if !q.Has(key) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(q.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", key)
+	}
+	return &n, nil
+}
+
+func optInt64(q url.Values, key string) (*int64, error) {
+	if !q.Has(key) {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(q.Get(key), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", key)
+	}
+	return &n, nil
+}
+
+func optTime(q url.Values, key string) (*time.Time, error) {
+	if !q.Has(key) {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, q.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", key)
+	}
+	return &t, nil
+}
+
+func parseVehicleFilter(q url.Values) (domain.VehicleFilter, error) {
+	f := domain.VehicleFilter{
+		Status:       list(q, "status"),
+		TractionType: list(q, "tractionType"),
+		ModelCode:    optString(q, "modelCode"),
+		BatchNumber:  optString(q, "batchNumber"),
+		Color:        optString(q, "color"),
+	}
+	for _, s := range f.Status {
+		if !slices.Contains(domain.VehicleStatuses, s) {
+			return f, fmt.Errorf("unknown status %q", s)
+		}
+	}
+	for _, t := range f.TractionType {
+		if !slices.Contains(domain.TractionTypes, t) {
+			return f, fmt.Errorf("unknown tractionType %q", t)
+		}
+	}
+	var err error
+	ints := []struct {
+		key string
+		dst **int
+	}{
+		{"releaseYearFrom", &f.ReleaseYearFrom}, {"releaseYearTo", &f.ReleaseYearTo},
+		{"mileageMin", &f.MileageMin}, {"mileageMax", &f.MileageMax},
+	}
+	for _, p := range ints {
+		if *p.dst, err = optInt(q, p.key); err != nil {
+			return f, err
+		}
+	}
+	times := []struct {
+		key string
+		dst **time.Time
+	}{
+		{"createdAfter", &f.CreatedAfter}, {"createdBefore", &f.CreatedBefore},
+		{"updatedAfter", &f.UpdatedAfter}, {"updatedBefore", &f.UpdatedBefore},
+	}
+	for _, p := range times {
+		if *p.dst, err = optTime(q, p.key); err != nil {
+			return f, err
+		}
+	}
+	if q.Has("includeDeleted") {
+		if f.IncludeDeleted, err = strconv.ParseBool(q.Get("includeDeleted")); err != nil {
+			return f, fmt.Errorf("includeDeleted must be a boolean")
+		}
+	}
+	return f, nil
+}
+
+func parseVehicleSort(q url.Values) (domain.VehicleSort, error) {
+	s := domain.DefaultVehicleSort
+	if v := strings.ToUpper(q.Get("sort")); v != "" {
+		s.Field = v
+		if s.Column() == "created_at" && v != domain.SortCreatedAt {
+			return s, fmt.Errorf("unknown sort %q", v)
+		}
+	}
+	switch strings.ToUpper(q.Get("direction")) {
+	case "", "DESC":
+	case "ASC":
+		s.Desc = false
+	default:
+		return s, fmt.Errorf("direction must be ASC or DESC")
+	}
+	return s, nil
+}
+
+func parseMovementFilter(q url.Values) (domain.MovementFilter, error) {
+	f := domain.MovementFilter{Types: list(q, "type")}
+	var err error
+	if c := q.Get("correlationId"); c != "" {
+		if _, err = uuid.Parse(c); err != nil {
+			return f, fmt.Errorf("correlationId must be a UUID")
+		}
+		f.CorrelationID = &c
+	}
+	if f.VehicleID, err = optInt64(q, "vehicleId"); err != nil {
+		return f, err
+	}
+	if f.From, err = optTime(q, "from"); err != nil {
+		return f, err
+	}
+	if f.To, err = optTime(q, "to"); err != nil {
+		return f, err
+	}
+	if q.Has("includeDeleted") {
+		if f.IncludeDeleted, err = strconv.ParseBool(q.Get("includeDeleted")); err != nil {
+			return f, fmt.Errorf("includeDeleted must be a boolean")
+		}
+	}
+	return f, nil
+}
+
+// optID is id, or nil (an empty cell) when it is 0.
+func optID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,113 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// xlsxWriter produces a single-sheet workbook. The sheet XML is streamed straight into the zip
+// entry, so memory use does not grow with the number of rows. Strings are written inline, which
+// avoids having to buffer a shared-strings table.
+type xlsxWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+	err   error
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	xlsxSheetOpen = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetClose = `</sheetData></worksheet>`
+)
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	x := &xlsxWriter{zw: zip.NewWriter(w)}
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	} {
+		x.writePart(part.name, part.body)
+	}
+	if x.err == nil {
+		x.sheet, x.err = x.zw.Create("xl/worksheets/sheet1.xml")
+	}
+	x.write(xlsxSheetOpen)
+	return x
+}
+
+func (x *xlsxWriter) writePart(name, body string) {
+	if x.err != nil {
+		return
+	}
+	var f io.Writer
+	if f, x.err = x.zw.Create(name); x.err == nil {
+		_, x.err = io.WriteString(f, body)
+	}
+}
+
+func (x *xlsxWriter) write(s string) {
+	if x.err == nil {
+		_, x.err = io.WriteString(x.sheet, s)
+	}
+}
+
+func (x *xlsxWriter) Header(cols []string) error {
+	vals := make([]any, len(cols))
+	for i, c := range cols {
+		vals[i] = c
+	}
+	return x.Row(vals)
+}
+
+func (x *xlsxWriter) Row(vals []any) error {
+	x.row++
+	var b strings.Builder
+	b.WriteString(`<row r="` + strconv.Itoa(x.row) + `">`)
+	for _, v := range vals {
+		switch n := v.(type) {
+		case nil:
+			b.WriteString(`<c/>`)
+		case int, int32, int64, float64:
+			b.WriteString(`<c t="n"><v>` + formatValue(n) + `</v></c>`)
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			_ = xml.EscapeText(&b, []byte(formatValue(v)))
+			b.WriteString(`</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+	x.write(b.String())
+	return x.err
+}
+
+func (x *xlsxWriter) Close() error {
+	x.write(xlsxSheetClose)
+	if x.err != nil {
+		return x.err
+	}
+	return x.zw.Close()
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	httpx "github.com/Kenfoxfire/Gear-Core-app/internal/http"
+)
+
+// Directives returns the implementations of the access-control directives declared in the schema.
+func Directives(authz *domain.Authorizer) DirectiveRoot {
+	return DirectiveRoot{
+		Auth:          Auth,
+		HasRole:       HasRole,
+		HasPermission: HasPermission(authz),
+		Public:        Public,
+	}
+}
+
+// Auth implements the @auth directive: the field resolves only for a signed-in user.
+func Auth(ctx context.Context, obj any, next graphql.Resolver) (any, error) {
+	if _, _, ok := httpx.UserFrom(ctx); !ok {
+		return nil, httpx.ErrUnauthenticated
+	}
+	return next(ctx)
+}
+
+// HasRole implements the @hasRole directive: the caller's role must be one of roles.
+func HasRole(ctx context.Context, obj any, next graphql.Resolver, roles []string) (any, error) {
+	_, role, ok := httpx.UserFrom(ctx)
+	if !ok {
+		return nil, httpx.ErrUnauthenticated
+	}
+	if !slices.Contains(roles, role) {
+		return nil, httpx.ErrForbidden
+	}
+	return next(ctx)
+}
+
+// Public implements the @public directive. It only marks a field as intentionally open.
+func Public(ctx context.Context, obj any, next graphql.Resolver) (any, error) {
+	return next(ctx)
+}
+
+// accessDirectives are the directives that satisfy CheckAccessDirectives.
+var accessDirectives = []string{"auth", "hasRole", "hasPermission", "public"}
+
+// CheckAccessDirectives returns an error naming every Query, Mutation and Subscription field
+// without an access-control directive, so an unprotected field fails at startup instead of shipping.
+// It also rejects @hasRole naming anything but a built-in role, since custom roles may be deleted.
+func CheckAccessDirectives(schema *ast.Schema) error {
+	if err := checkHasRoleNames(schema); err != nil {
+		return err
+	}
+	var missing []string
+	for _, root := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+		if root == nil {
+			continue
+		}
+		for _, f := range root.Fields {
+			if strings.HasPrefix(f.Name, "__") {
+				continue
+			}
+			guarded := slices.ContainsFunc(f.Directives, func(d *ast.Directive) bool {
+				return slices.Contains(accessDirectives, d.Name)
+			})
+			if !guarded {
+				missing = append(missing, root.Name+"."+f.Name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("fields without @auth, @hasRole, @hasPermission or @public: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func checkHasRoleNames(schema *ast.Schema) error {
+	var bad []string
+	for _, def := range schema.Types {
+		for _, f := range def.Fields {
+			d := f.Directives.ForName("hasRole")
+			if d == nil {
+				continue
+			}
+			if arg := d.Arguments.ForName("roles"); arg != nil {
+				for _, role := range arg.Value.Children {
+					if !domain.IsBuiltinRole(role.Value.Raw) {
+						bad = append(bad, fmt.Sprintf("%s.%s (%s)", def.Name, f.Name, role.Value.Raw))
+					}
+				}
+			}
+		}
+	}
+	if len(bad) > 0 {
+		sort.Strings(bad)
+		return fmt.Errorf("@hasRole naming a role that is not built in: %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// HasPermission implements the @hasPermission directive: the field resolves only when the
+// caller's role holds perm.
+func HasPermission(authz *domain.Authorizer) func(ctx context.Context, obj any, next graphql.Resolver, perm string) (any, error) {
+	return func(ctx context.Context, obj any, next graphql.Resolver, perm string) (any, error) {
+		if err := checkPermission(ctx, authz, perm); err != nil {
+			return nil, err
+		}
+		return next(ctx)
+	}
+}
+
+// requirePermission is for checks that depend on arguments, such as includeDeleted.
+func (r *Resolver) requirePermission(ctx context.Context, perm string) error {
+	return checkPermission(ctx, r.Authz, perm)
+}
+
+func checkPermission(ctx context.Context, authz *domain.Authorizer, perm string) error {
+	_, role, ok := httpx.UserFrom(ctx)
+	if !ok {
+		return httpx.ErrUnauthenticated
+	}
+	allowed, err := authz.Can(ctx, role, perm)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return httpx.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+)
+
+// ErrorPresenter gives every error an extensions.code. apperr errors keep their message (plus the
+// offending fields for validation errors); gqlgen's own request errors pass through; anything else
+// is logged and reported as INTERNAL without its text.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+
+	var te *domain.TransitionError
+	var ae *apperr.Error
+	switch {
+	case errors.As(err, &te):
+		gqlErr.Message = te.Error()
+		gqlErr.Extensions["code"] = apperr.CodeIllegalTransition
+		gqlErr.Extensions["movement"] = te.Movement
+		gqlErr.Extensions["status"] = te.Status
+		if te.VIN != "" {
+			gqlErr.Extensions["vin"] = te.VIN
+		}
+	case errors.As(err, &ae) && ae.Code != apperr.CodeInternal:
+		gqlErr.Message = ae.Message
+		gqlErr.Extensions["code"] = ae.Code
+		if len(ae.Fields) > 0 {
+			fields := make([]map[string]any, len(ae.Fields))
+			for i, f := range ae.Fields {
+				fields[i] = map[string]any{"path": f.Path, "message": f.Message}
+			}
+			gqlErr.Extensions["fields"] = fields
+		}
+	case isRequestError(err):
+		if _, ok := gqlErr.Extensions["code"]; !ok {
+			gqlErr.Extensions["code"] = apperr.CodeValidation
+		}
+	default:
+		log.Printf("graphql %s: %v", gqlErr.Path, err)
+		gqlErr.Message = "internal error"
+		gqlErr.Extensions = map[string]any{"code": apperr.CodeInternal}
+	}
+	return gqlErr
+}
+
+// underArg re-roots the field paths of a domain validation error, which are relative to the
+// input object, under the GraphQL argument holding it. Other errors are returned unchanged.
+func underArg(arg string, err error) error {
+	var ae *apperr.Error
+	if !errors.As(err, &ae) || ae.Code != apperr.CodeValidation {
+		return err
+	}
+	fields := make([]apperr.FieldError, len(ae.Fields))
+	for i, f := range ae.Fields {
+		fields[i] = apperr.FieldError{Path: append([]string{arg}, f.Path...), Message: f.Message}
+	}
+	return apperr.Validation(fields...)
+}
+
+// isRequestError reports errors raised by gqlgen itself while reading the request, such as
+// argument coercion failures: gqlgen hands those over already wrapped in a *gqlerror.Error,
+// while resolver errors arrive as returned.
+func isRequestError(err error) bool {
+	_, ok := err.(*gqlerror.Error)
+	return ok
+}
+
+// Recover turns a resolver panic into an INTERNAL error; the stack goes to the log only.
+func Recover(ctx context.Context, p any) error {
+	log.Printf("graphql panic: %v\n%s", p, debug.Stack())
+	return apperr.Internal(nil)
+}
@@ -3,21 +3,34 @@
 package graph
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"sort"
 	"strconv"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/graph/loaders"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/graph/model"
 )
 
-// parseID converts a GraphQL string ID to int64
-func parseID(id string) int64 {
+// parseID converts a GraphQL string ID to int64; path names the argument for the validation error.
+func parseID(path, id string) (int64, error) {
 	n, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		panic(errors.New("invalid ID format: must be numeric"))
+		return 0, apperr.Invalid(path, "must be a numeric ID")
 	}
-	return n
+	return n, nil
+}
+
+// parseOptID parses an optional ID; null is 0.
+func parseOptID(path string, id *string) (int64, error) {
+	if id == nil {
+		return 0, nil
+	}
+	return parseID(path, *id)
 }
 
 // int64 → string
@@ -25,12 +38,41 @@ func idStr(n int64) string {
 	return strconv.FormatInt(n, 10)
 }
 
-func ptrStr(s *string) string { return *s }
+// optID maps 0 (NULL) to null.
+func optID(n int64) *string {
+	if n == 0 {
+		return nil
+	}
+	return strToPtr(idStr(n))
+}
+
+func ptrStr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
 
 func strToPtr(s string) *string {
 	return &s
 }
 
+// optStr maps "" to null.
+func optStr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optStatus(s string) *model.VehicleStatus {
+	if s == "" {
+		return nil
+	}
+	status := model.VehicleStatus(s)
+	return &status
+}
+
 func ptrInt32ToInt(p *int32, fallback int) int {
 	if p == nil {
 		return fallback
@@ -38,39 +80,138 @@ func ptrInt32ToInt(p *int32, fallback int) int {
 	return int(*p)
 }
 
+// mapRole maps ro, including its permissions when preloaded; otherwise they are resolved by ID.
+func mapRole(ro *domain.Role) *model.Role {
+	out := &model.Role{
+		ID:        strconv.FormatInt(ro.ID, 10),
+		Name:      ro.Name,
+		BuiltIn:   domain.IsBuiltinRole(ro.Name),
+		CreatedAt: ro.CreatedAt,
+	}
+	if ro.Permissions != nil {
+		out.Permissions = make([]string, 0, len(ro.Permissions))
+		for _, p := range ro.Permissions {
+			out.Permissions = append(out.Permissions, p.Name)
+		}
+	}
+	return out
+}
+
+// mapUser maps u, including its role when preloaded; otherwise the role is resolved by RoleID.
 func mapUser(u *domain.User) *model.User {
 	var gqlRole *model.Role
 	if u.Role != nil {
-		gqlRole = &model.Role{
-			ID:        strconv.FormatInt(u.Role.ID, 10),
-			Name:      u.Role.Name,
-			CreatedAt: u.Role.CreatedAt,
-		}
+		gqlRole = mapRole(u.Role)
 	}
 	return &model.User{
 		ID:        strconv.FormatInt(u.ID, 10),
 		Email:     u.Email,
 		Role:      gqlRole,
+		RoleID:    u.RoleID,
 		CreatedAt: u.CreatedAt,
 	}
 }
+
+// mapAuthPayload returns the tokens of a new session for u along with u itself.
+func mapAuthPayload(u *domain.User, t *domain.Tokens) *model.AuthPayload {
+	return &model.AuthPayload{
+		Token: t.AccessToken, RefreshToken: t.RefreshToken, ExpiresAt: t.ExpiresAt, User: mapUser(u),
+	}
+}
+
 func mapReport(rows []domain.MovementReportRow) []*model.MovementReportRow {
 	mapped := make([]*model.MovementReportRow, 0, len(rows))
 	for i := range rows {
 		r := rows[i]
 		mapped = append(mapped, &model.MovementReportRow{
-			Type:  model.MovementType(r.Type),
+			Type:  r.Type,
 			Count: int32(r.Count),
 		})
 	}
 	return mapped
 }
+func mapSeries(series []domain.MovementSeries) []*model.MovementSeries {
+	out := make([]*model.MovementSeries, 0, len(series))
+	for _, s := range series {
+		buckets := make([]*model.MovementBucket, 0, len(s.Points))
+		for _, p := range s.Points {
+			buckets = append(buckets, &model.MovementBucket{Start: p.Start, Count: int32(p.Count)})
+		}
+		out = append(out, &model.MovementSeries{Key: optStr(s.Key), Total: int32(s.Total), Buckets: buckets})
+	}
+	return out
+}
+
+func mapFleetStats(s *domain.FleetStats) *model.FleetStats {
+	out := &model.FleetStats{
+		TotalVehicles: int32(s.TotalVehicles), SoldVehicles: int32(s.Sold),
+		ReturnedAfterSale: int32(s.ReturnedAfterSale), ReturnRateAfterSale: s.ReturnRate(),
+		MeanDaysToSale: s.MeanDaysToSale, ComputedAt: s.ComputedAt,
+		ByStatus:          make([]*model.StatusCount, 0, len(s.ByStatus)),
+		ByTractionType:    make([]*model.TractionCount, 0, len(s.ByTractionType)),
+		MileageByModel:    make([]*model.ModelMileage, 0, len(s.MileageByModel)),
+		DefectRateByBatch: make([]*model.BatchDefectRate, 0, len(s.DefectsByBatch)),
+	}
+	for _, c := range s.ByStatus {
+		out.ByStatus = append(out.ByStatus, &model.StatusCount{Status: model.VehicleStatus(c.Key), Count: int32(c.Count)})
+	}
+	for _, c := range s.ByTractionType {
+		out.ByTractionType = append(out.ByTractionType, &model.TractionCount{TractionType: model.TractionType(c.Key), Count: int32(c.Count)})
+	}
+	for _, m := range s.MileageByModel {
+		out.MileageByModel = append(out.MileageByModel, &model.ModelMileage{
+			ModelCode: m.ModelCode, Vehicles: int32(m.Vehicles), AverageMileage: m.AverageMileage,
+		})
+	}
+	for _, b := range s.DefectsByBatch {
+		out.DefectRateByBatch = append(out.DefectRateByBatch, &model.BatchDefectRate{
+			BatchNumber: b.BatchNumber, Vehicles: int32(b.Vehicles), Defects: int32(b.Defects), DefectRate: b.Rate(),
+		})
+	}
+	return out
+}
+
+func mapBatch(b *domain.Batch) *model.Batch {
+	out := &model.Batch{
+		Number: b.Number, VehicleCount: int32(b.Vehicles), ModelCodes: b.ModelCodes,
+		AverageMileage: b.AverageMileage, Defects: int32(b.Defects),
+		FirstCreatedAt: b.FirstCreatedAt, LastCreatedAt: b.LastCreatedAt,
+		ByStatus: make([]*model.StatusCount, 0, 4),
+	}
+	if out.ModelCodes == nil {
+		out.ModelCodes = []string{}
+	}
+	for _, c := range b.ByStatus() {
+		out.ByStatus = append(out.ByStatus, &model.StatusCount{Status: model.VehicleStatus(c.Key), Count: int32(c.Count)})
+	}
+	return out
+}
+
 func mapVehicle(v *domain.Vehicle) *model.Vehicle {
 	return &model.Vehicle{
 		ID: idStr(v.ID), Vin: v.VIN, Name: v.Name, ModelCode: v.ModelCode,
 		TractionType: model.TractionType(v.TractionType), ReleaseYear: int32(v.ReleaseYear),
 		BatchNumber: v.BatchNumber, Color: strToPtr(v.Color), Mileage: int32(v.Mileage),
 		Status: model.VehicleStatus(v.Status), CreatedAt: v.CreatedAt, UpdatedAt: v.UpdatedAt,
+		DeletedAt: v.DeletedAt, Manufacturer: optStr(v.Manufacturer), PlantCode: optStr(v.PlantCode),
+		CurrentLocationID:   optID(v.CurrentLocationID),
+		ReleaseYearMismatch: domain.ReleaseYearMismatch(v.VIN, v.ReleaseYear, time.Now()),
+	}
+}
+
+func mapVinDecoding(d *domain.VINInfo) *model.VinDecoding {
+	years := make([]int32, 0, len(d.ModelYearCandidates))
+	for _, y := range d.ModelYearCandidates {
+		years = append(years, int32(y))
+	}
+	var year *int32
+	if d.ModelYear != 0 {
+		year = &years[0]
+	}
+	return &model.VinDecoding{
+		Vin: d.VIN, Wmi: d.WMI, Manufacturer: optStr(d.Manufacturer), Country: optStr(d.Country),
+		Region: optStr(d.Region), ModelYear: year, ModelYearCandidates: years,
+		PlantCode: d.PlantCode, SerialNumber: d.SerialNumber, CheckDigitValid: d.CheckDigitValid,
 	}
 }
 func mapMovement(m *domain.Movement) *model.Movement {
@@ -83,8 +224,365 @@ func mapMovement(m *domain.Movement) *model.Movement {
 	}
 	return &model.Movement{
 		ID: idStr(m.ID), VehicleID: idStr(m.VehicleID),
-		Type: model.MovementType(m.Type), Description: &m.Description,
-		OccurredAt: m.OccurredAt, Metadata: metadataStr,
-		CreatedBy: idStr(m.CreatedBy), CreatedAt: m.CreatedAt,
+		Type: m.Type, Description: &m.Description,
+		OccurredAt: m.OccurredAt, Metadata: metadataStr, Details: mapMovementDetails(m),
+		FromLocationID: optID(m.FromLocationID), ToLocationID: optID(m.ToLocationID),
+		FromStatus: optStatus(m.FromStatus), ToStatus: optStatus(m.ToStatus),
+		VoidedAt: m.VoidedAt, VoidedByID: optID(m.VoidedBy), VoidReason: optStr(m.VoidReason),
+		ReversesID: optID(m.ReversesID), CorrelationID: optStr(m.CorrelationID), CreatedByID: idStr(m.CreatedBy), CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func mapMovementDetails(m *domain.Movement) model.MovementDetails {
+	switch d := m.Details().(type) {
+	case *domain.SaleDetails:
+		return model.SaleDetails{Price: d.Price, Currency: d.Currency, Buyer: d.Buyer}
+	case *domain.DefectDetails:
+		return model.DefectDetails{Code: d.Code, Severity: d.Severity}
+	case *domain.TransferDetails:
+		return model.TransferDetails{From: d.From, To: d.To}
+	}
+	return nil
+}
+
+func mapMovementSchema(t *domain.MovementType) *model.MovementMetadataSchema {
+	return &model.MovementMetadataSchema{
+		Type: t.Name, Schema: ptrStr(jsonStr(t.MetadataSchema)),
+		UpdatedByID: optID(t.UpdatedBy), UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func mapMovementType(t *domain.MovementType) *model.MovementType {
+	out := &model.MovementType{
+		Name: t.Name, Description: optStr(t.Description), ToStatus: optStatus(t.ToStatus), LocationRule: model.LocationRule(t.LocationRule),
+		BuiltIn: t.BuiltIn, CreatedAt: t.CreatedAt, UpdatedAt: t.UpdatedAt,
+	}
+	for _, s := range t.FromStatuses {
+		out.FromStatuses = append(out.FromStatuses, model.VehicleStatus(s))
+	}
+	if t.MetadataSchema != nil {
+		out.MetadataSchema = jsonStr(t.MetadataSchema)
+	}
+	return out
+}
+
+func vehicleStatuses(statuses []model.VehicleStatus) []string {
+	out := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		out = append(out, string(s))
+	}
+	return out
+}
+
+// pageArgs builds keyset page arguments from the GraphQL first/after pair of the connection field
+// being resolved. The total is only counted when the query selects totalCount.
+func pageArgs(ctx context.Context, first *int32, after *string, fallback int) (domain.PageArgs, error) {
+	if first != nil && *first < 1 {
+		return domain.PageArgs{}, apperr.Invalid("first", "must be at least 1")
+	}
+	cur, err := domain.DecodeCursor(after)
+	if err != nil {
+		return domain.PageArgs{}, err
+	}
+	page := domain.PageArgs{First: ptrInt32ToInt(first, fallback), After: cur}
+	for _, f := range graphql.CollectFieldsCtx(ctx, nil) {
+		if f.Name == "totalCount" {
+			page.CountTotal = true
+		}
+	}
+	return page, nil
+}
+
+func mapPageInfo(hasNext bool, after *domain.Cursor, start, end *string) *model.PageInfo {
+	return &model.PageInfo{
+		HasNextPage: hasNext, HasPreviousPage: after != nil,
+		StartCursor: start, EndCursor: end,
+	}
+}
+
+func mapVehicleConnection(p *domain.Page[*domain.Vehicle], after *domain.Cursor) *model.VehicleConnection {
+	edges := make([]*model.VehicleEdge, 0, len(p.Items))
+	for i, v := range p.Items {
+		edges = append(edges, &model.VehicleEdge{Cursor: p.Cursors[i].Encode(), Node: mapVehicle(v)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.VehicleConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func mapUserConnection(p *domain.Page[*domain.User], after *domain.Cursor) *model.UserConnection {
+	edges := make([]*model.UserEdge, 0, len(p.Items))
+	for i, u := range p.Items {
+		edges = append(edges, &model.UserEdge{Cursor: p.Cursors[i].Encode(), Node: mapUser(u)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.UserConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func mapBatchConnection(p *domain.Page[*domain.Batch], after *domain.Cursor) *model.BatchConnection {
+	edges := make([]*model.BatchEdge, 0, len(p.Items))
+	for i, b := range p.Items {
+		edges = append(edges, &model.BatchEdge{Cursor: p.Cursors[i].Encode(), Node: mapBatch(b)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.BatchConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func mapMovementConnection(p *domain.Page[*domain.Movement], after *domain.Cursor) *model.MovementConnection {
+	edges := make([]*model.MovementEdge, 0, len(p.Items))
+	for i, m := range p.Items {
+		edges = append(edges, &model.MovementEdge{Cursor: p.Cursors[i].Encode(), Node: mapMovement(m)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.MovementConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func ptrInt32ToIntPtr(p *int32) *int {
+	if p == nil {
+		return nil
+	}
+	n := int(*p)
+	return &n
+}
+
+func mapVehicleFilter(f *model.VehicleFilter) domain.VehicleFilter {
+	if f == nil {
+		return domain.VehicleFilter{}
+	}
+	out := domain.VehicleFilter{
+		ModelCode: f.ModelCode, BatchNumber: f.BatchNumber, Color: f.Color,
+		ReleaseYearFrom: ptrInt32ToIntPtr(f.ReleaseYearFrom), ReleaseYearTo: ptrInt32ToIntPtr(f.ReleaseYearTo),
+		MileageMin: ptrInt32ToIntPtr(f.MileageMin), MileageMax: ptrInt32ToIntPtr(f.MileageMax),
+		CreatedAfter: f.CreatedAfter, CreatedBefore: f.CreatedBefore,
+		UpdatedAfter: f.UpdatedAfter, UpdatedBefore: f.UpdatedBefore,
+	}
+	for _, s := range f.Status {
+		out.Status = append(out.Status, string(s))
+	}
+	for _, t := range f.TractionType {
+		out.TractionType = append(out.TractionType, string(t))
+	}
+	return out
+}
+
+func mapVehicleSort(sort *model.VehicleSort, dir *model.SortDirection) domain.VehicleSort {
+	out := domain.DefaultVehicleSort
+	if sort != nil {
+		out.Field = string(*sort)
+	}
+	if dir != nil {
+		out.Desc = *dir == model.SortDirectionDesc
+	}
+	return out
+}
+
+func mapSearchHit(h *domain.VehicleSearchHit) *model.VehicleSearchResult {
+	highlights := make([]*model.SearchHighlight, 0, len(h.Highlights))
+	for _, hl := range h.Highlights {
+		ranges := make([]*model.HighlightRange, 0, len(hl.Ranges))
+		for _, rg := range hl.Ranges {
+			ranges = append(ranges, &model.HighlightRange{Start: int32(rg[0]), End: int32(rg[1])})
+		}
+		highlights = append(highlights, &model.SearchHighlight{Field: hl.Field, Value: hl.Value, Ranges: ranges})
+	}
+	return &model.VehicleSearchResult{Vehicle: mapVehicle(&h.Vehicle), Score: h.Score, Highlights: highlights}
+}
+
+// mapFieldChanges lists changes by field name.
+func mapFieldChanges(changes map[string]domain.FieldChange) []*model.FieldChange {
+	fields := make([]string, 0, len(changes))
+	for f := range changes {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	out := make([]*model.FieldChange, 0, len(fields))
+	for _, f := range fields {
+		c := changes[f]
+		out = append(out, &model.FieldChange{Field: f, Old: jsonStr(c.Old), New: jsonStr(c.New)})
+	}
+	return out
+}
+
+func mapAuditEntry(a *domain.VehicleAudit) *model.VehicleAuditEntry {
+	return &model.VehicleAuditEntry{
+		ID: idStr(a.ID), VehicleID: idStr(a.VehicleID), Action: model.AuditAction(a.Action),
+		ActorID: optID(a.ActorID), Changes: mapFieldChanges(a.Changes), CreatedAt: a.CreatedAt,
+	}
+}
+
+func mapMovementCorrection(a *domain.MovementAudit) *model.MovementCorrection {
+	return &model.MovementCorrection{
+		ID: idStr(a.ID), MovementID: idStr(a.MovementID), Action: model.MovementCorrectionAction(a.Action),
+		ActorID: optID(a.ActorID), Changes: mapFieldChanges(a.Changes), Reason: optStr(a.Reason),
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+// jsonStr encodes v for a JSON scalar field, mapping nil to null.
+func jsonStr(v any) *string {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return strToPtr(string(data))
+}
+
+func mapAuditConnection(p *domain.Page[*domain.VehicleAudit], after *domain.Cursor) *model.VehicleAuditConnection {
+	edges := make([]*model.VehicleAuditEdge, 0, len(p.Items))
+	for i, a := range p.Items {
+		edges = append(edges, &model.VehicleAuditEdge{Cursor: p.Cursors[i].Encode(), Node: mapAuditEntry(a)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.VehicleAuditConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func mapAuditFilter(f *model.AuditFilter) (domain.AuditFilter, error) {
+	if f == nil {
+		return domain.AuditFilter{}, nil
+	}
+	out := domain.AuditFilter{From: f.From, To: f.To}
+	if f.VehicleID != nil {
+		id, err := parseID("filter.vehicleId", *f.VehicleID)
+		if err != nil {
+			return out, err
+		}
+		out.VehicleID = &id
+	}
+	if f.ActorID != nil {
+		id, err := parseID("filter.actorId", *f.ActorID)
+		if err != nil {
+			return out, err
+		}
+		out.ActorID = &id
+	}
+	for _, a := range f.Action {
+		out.Actions = append(out.Actions, string(a))
+	}
+	return out, nil
+}
+
+func mapDomainEvent(e *domain.DomainEvent) *model.DomainEvent {
+	var actorID *string
+	if e.ActorID != 0 {
+		actorID = strToPtr(idStr(e.ActorID))
+	}
+	return &model.DomainEvent{
+		Seq: idStr(e.Seq), Type: model.DomainEventType(e.Type), AggregateType: e.AggregateType,
+		AggregateID: idStr(e.AggregateID), ActorID: actorID, Payload: jsonStr(e.Payload), CreatedAt: e.CreatedAt,
+	}
+}
+
+func mapEventConnection(p *domain.Page[*domain.DomainEvent], after *domain.Cursor) *model.DomainEventConnection {
+	edges := make([]*model.DomainEventEdge, 0, len(p.Items))
+	for i, e := range p.Items {
+		edges = append(edges, &model.DomainEventEdge{Cursor: p.Cursors[i].Encode(), Node: mapDomainEvent(e)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.DomainEventConnection{Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end)}
+}
+
+func mapLocation(l *domain.Location) *model.Location {
+	return &model.Location{
+		ID: idStr(l.ID), Name: l.Name, Kind: model.LocationKind(l.Kind), Address: optStr(l.Address),
+		CreatedAt: l.CreatedAt, UpdatedAt: l.UpdatedAt,
+	}
+}
+
+// loadLocation resolves an optional location ID through the request's loader.
+func (r *Resolver) loadLocation(ctx context.Context, path string, id *string) (*model.Location, error) {
+	if id == nil {
+		return nil, nil
+	}
+	lid, err := parseID(path, *id)
+	if err != nil {
+		return nil, err
+	}
+	l, err := loaders.For(ctx, r.Repos).LocationByID.Load(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+	return mapLocation(l), nil
+}
+
+func mapWebhook(w *domain.Webhook) *model.Webhook {
+	return &model.Webhook{
+		ID: idStr(w.ID), URL: w.URL, Events: w.Events, Active: w.Active,
+		CreatedAt: w.CreatedAt, UpdatedAt: w.UpdatedAt,
+	}
+}
+
+func mapWebhookDelivery(d *domain.WebhookDelivery) *model.WebhookDelivery {
+	out := &model.WebhookDelivery{
+		ID: idStr(d.ID), WebhookID: idStr(d.WebhookID), EventID: idStr(d.EventID),
+		Event: d.EventType, Body: d.Body, Status: model.WebhookDeliveryStatus(d.Status),
+		Attempts: int32(d.Attempts), LastAttemptAt: d.LastAttemptAt, LastError: optStr(d.LastError),
+		CreatedAt: d.CreatedAt,
+	}
+	if d.Status == domain.DeliveryPending {
+		out.NextAttemptAt = &d.NextAttemptAt
+	}
+	if d.ResponseStatus != 0 {
+		code := int32(d.ResponseStatus)
+		out.ResponseStatus = &code
+	}
+	return out
+}
+
+func mapDeliveryConnection(p *domain.Page[*domain.WebhookDelivery], after *domain.Cursor) *model.WebhookDeliveryConnection {
+	edges := make([]*model.WebhookDeliveryEdge, 0, len(p.Items))
+	for i, d := range p.Items {
+		edges = append(edges, &model.WebhookDeliveryEdge{Cursor: p.Cursors[i].Encode(), Node: mapWebhookDelivery(d)})
+	}
+	var start, end *string
+	if len(edges) > 0 {
+		start, end = &edges[0].Cursor, &edges[len(edges)-1].Cursor
+	}
+	return &model.WebhookDeliveryConnection{
+		Edges: edges, PageInfo: mapPageInfo(p.HasNextPage, after, start, end), TotalCount: int32(p.TotalCount),
+	}
+}
+
+func mapImportResult(res *domain.ImportResult) *model.ImportResult {
+	errs := make([]*model.ImportRowError, 0, len(res.Errors))
+	for _, e := range res.Errors {
+		var field *string
+		if e.Field != "" {
+			field = strToPtr(e.Field)
+		}
+		errs = append(errs, &model.ImportRowError{Line: int32(e.Line), Field: field, Message: e.Message})
+	}
+	return &model.ImportResult{
+		DryRun: res.DryRun, Total: int32(res.Total), Created: int32(res.Created),
+		Updated: int32(res.Updated), Unchanged: int32(res.Unchanged), Errors: errs,
 	}
 }
@@ -3,14 +3,29 @@ package loaders
 
 import (
 	"context"
+	"net/http"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/vikstrous/dataloadgen"
 )
 
+// MovementPageKey identifies the first page of a vehicle's movements.
+type MovementPageKey struct {
+	VehicleID int64
+	First     int
+}
+
 type Loaders struct {
-	VehicleByID *dataloadgen.Loader[int64, *domain.Vehicle]
+	VehicleByID        *dataloadgen.Loader[int64, *domain.Vehicle]
+	UserByID           *dataloadgen.Loader[int64, *domain.User]
+	RoleByID           *dataloadgen.Loader[int64, *domain.Role]
+	PermissionsByRole  *dataloadgen.Loader[int64, []string]
+	MovementsByVehicle *dataloadgen.Loader[MovementPageKey, *domain.Page[*domain.Movement]]
+	LocationByID       *dataloadgen.Loader[int64, *domain.Location]
+	ReversalOf         *dataloadgen.Loader[int64, int64] // 0 when the movement is not reversed
 }
 
 type CtxKey string
@@ -18,34 +33,69 @@ type CtxKey string
 const Key CtxKey = "dataloaders"
 
 func New(repos *domain.Repos) *Loaders {
-	vehicleBatch := func(ctx context.Context, keys []int64) ([]*domain.Vehicle, []error) {
-		res := make([]*domain.Vehicle, len(keys))
-		errs := make([]error, len(keys))
-
-		var items []*domain.Vehicle
-		_ = repos.DB.WithContext(ctx).
-			Model(&items).
-			Where("id IN (?)", pgIn(keys)).
-			Select()
-
-		m := make(map[int64]*domain.Vehicle, len(items))
-		for _, v := range items {
-			m[v.ID] = v
+	opts := []dataloadgen.Option{
+		dataloadgen.WithWait(1 * time.Millisecond),
+		dataloadgen.WithBatchCapacity(100),
+	}
+
+	movementsBatch := func(ctx context.Context, keys []MovementPageKey) (map[MovementPageKey]*domain.Page[*domain.Movement], error) {
+		// Keys nearly always share the same page size; batch once per distinct size.
+		bySize := map[int][]int64{}
+		for _, k := range keys {
+			bySize[k.First] = append(bySize[k.First], k.VehicleID)
 		}
-		for i, k := range keys {
-			res[i] = m[k]
+		out := make(map[MovementPageKey]*domain.Page[*domain.Movement], len(keys))
+		for first, ids := range bySize {
+			pages, err := repos.ListMovementsForVehicles(ctx, ids, first)
+			if err != nil {
+				return nil, err
+			}
+			for id, p := range pages {
+				out[MovementPageKey{VehicleID: id, First: first}] = p
+			}
 		}
-		return res, errs
+		return out, nil
 	}
 
 	return &Loaders{
-		VehicleByID: dataloadgen.NewLoader(
-			vehicleBatch,
-			dataloadgen.WithWait(1*time.Millisecond),
-			dataloadgen.WithBatchCapacity(100),
-		),
+		VehicleByID:        dataloadgen.NewMappedLoader(repos.GetVehiclesByIDs, opts...),
+		UserByID:           dataloadgen.NewMappedLoader(repos.GetUsersByIDs, opts...),
+		RoleByID:           dataloadgen.NewMappedLoader(repos.GetRolesByIDs, opts...),
+		PermissionsByRole:  dataloadgen.NewMappedLoader(repos.GetPermissionNamesByRoleIDs, opts...),
+		MovementsByVehicle: dataloadgen.NewMappedLoader(movementsBatch, opts...),
+		LocationByID:       dataloadgen.NewMappedLoader(repos.GetLocationsByIDs, opts...),
+		ReversalOf:         dataloadgen.NewMappedLoader(repos.GetReversalsOf, opts...),
+	}
+}
+
+// Middleware installs a fresh set of loaders into every request context, so caching
+// never outlives a single request.
+func Middleware(repos *domain.Repos) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), Key, New(repos))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
 	}
 }
 
-// helper para IN query
-func pgIn[T any](xs []T) []T { return xs }
+// PerEvent is a response middleware that gives each event of a subscription its own loaders.
+// Otherwise every event would share, and read stale rows from, the loaders Middleware installed
+// when the websocket connected.
+func PerEvent(repos *domain.Repos) graphql.ResponseMiddleware {
+	return func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		if op := graphql.GetOperationContext(ctx); op.Operation != nil && op.Operation.Operation == ast.Subscription {
+			ctx = context.WithValue(ctx, Key, New(repos))
+		}
+		return next(ctx)
+	}
+}
+
+// For returns the loaders installed by Middleware or PerEvent. When neither ran, it returns fresh
+// loaders over repos, which still resolve correctly but batch nothing across calls.
+func For(ctx context.Context, repos *domain.Repos) *Loaders {
+	if l, ok := ctx.Value(Key).(*Loaders); ok {
+		return l
+	}
+	return New(repos)
+}
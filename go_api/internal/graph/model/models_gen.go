@@ -10,141 +10,1131 @@ import (
 	"time"
 )
 
+type MovementDetails interface {
+	IsMovementDetails()
+}
+
+type AuditFilter struct {
+	VehicleID *string       `json:"vehicleId,omitempty"`
+	ActorID   *string       `json:"actorId,omitempty"`
+	Action    []AuditAction `json:"action,omitempty"`
+	From      *time.Time    `json:"from,omitempty"`
+	To        *time.Time    `json:"to,omitempty"`
+}
+
 type AuthPayload struct {
-	Token string `json:"token"`
-	User  *User  `json:"user"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	User         *User     `json:"user"`
+}
+
+type Batch struct {
+	Number         string             `json:"number"`
+	VehicleCount   int32              `json:"vehicleCount"`
+	ByStatus       []*StatusCount     `json:"byStatus"`
+	ModelCodes     []string           `json:"modelCodes"`
+	AverageMileage float64            `json:"averageMileage"`
+	Defects        int32              `json:"defects"`
+	FirstCreatedAt time.Time          `json:"firstCreatedAt"`
+	LastCreatedAt  time.Time          `json:"lastCreatedAt"`
+	Vehicles       *VehicleConnection `json:"vehicles"`
+}
+
+type BatchConnection struct {
+	Edges      []*BatchEdge `json:"edges"`
+	PageInfo   *PageInfo    `json:"pageInfo"`
+	TotalCount int32        `json:"totalCount"`
+}
+
+type BatchDefectRate struct {
+	BatchNumber string  `json:"batchNumber"`
+	Vehicles    int32   `json:"vehicles"`
+	Defects     int32   `json:"defects"`
+	DefectRate  float64 `json:"defectRate"`
+}
+
+type BatchEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Batch `json:"node"`
+}
+
+type BatchMovementInput struct {
+	Type         string    `json:"type"`
+	Description  *string   `json:"description,omitempty"`
+	OccurredAt   time.Time `json:"occurredAt"`
+	Metadata     *string   `json:"metadata,omitempty"`
+	ToLocationID *string   `json:"toLocationId,omitempty"`
+}
+
+type BatchMovementResult struct {
+	CorrelationID string      `json:"correlationId"`
+	Movements     []*Movement `json:"movements"`
+}
+
+type BatchVehicleUpdateInput struct {
+	ModelCode    *string       `json:"modelCode,omitempty"`
+	TractionType *TractionType `json:"tractionType,omitempty"`
+	ReleaseYear  *int32        `json:"releaseYear,omitempty"`
+	BatchNumber  *string       `json:"batchNumber,omitempty"`
+	Color        *string       `json:"color,omitempty"`
+	Manufacturer *string       `json:"manufacturer,omitempty"`
+	PlantCode    *string       `json:"plantCode,omitempty"`
+}
+
+type CreateWebhookPayload struct {
+	Webhook *Webhook `json:"webhook"`
+	Secret  string   `json:"secret"`
+}
+
+type DefectDetails struct {
+	Code     *string `json:"code,omitempty"`
+	Severity *string `json:"severity,omitempty"`
+}
+
+func (DefectDetails) IsMovementDetails() {}
+
+// An entry of the append-only domain event log. seq identifies the event; events are listed in an
+// order that never lets one commit behind a cursor, which need not be seq order. aggregateType is
+// vehicle, movement or user.
+type DomainEvent struct {
+	Seq           string          `json:"seq"`
+	Type          DomainEventType `json:"type"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateId"`
+	ActorID       *string         `json:"actorId,omitempty"`
+	Payload       *string         `json:"payload,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+type DomainEventConnection struct {
+	Edges    []*DomainEventEdge `json:"edges"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type DomainEventEdge struct {
+	Cursor string       `json:"cursor"`
+	Node   *DomainEvent `json:"node"`
+}
+
+type FieldChange struct {
+	Field string  `json:"field"`
+	Old   *string `json:"old,omitempty"`
+	New   *string `json:"new,omitempty"`
+}
+
+type FleetStats struct {
+	TotalVehicles       int32              `json:"totalVehicles"`
+	ByStatus            []*StatusCount     `json:"byStatus"`
+	ByTractionType      []*TractionCount   `json:"byTractionType"`
+	MileageByModel      []*ModelMileage    `json:"mileageByModel"`
+	DefectRateByBatch   []*BatchDefectRate `json:"defectRateByBatch"`
+	SoldVehicles        int32              `json:"soldVehicles"`
+	ReturnedAfterSale   int32              `json:"returnedAfterSale"`
+	ReturnRateAfterSale *float64           `json:"returnRateAfterSale,omitempty"`
+	MeanDaysToSale      *float64           `json:"meanDaysToSale,omitempty"`
+	ComputedAt          time.Time          `json:"computedAt"`
+}
+
+type HighlightRange struct {
+	Start int32 `json:"start"`
+	End   int32 `json:"end"`
+}
+
+// Outcome of importVehicles. Counts describe what was (or, on a dry run or failure, would have been)
+// written; when errors is non-empty nothing was written.
+type ImportResult struct {
+	DryRun    bool              `json:"dryRun"`
+	Total     int32             `json:"total"`
+	Created   int32             `json:"created"`
+	Updated   int32             `json:"updated"`
+	Unchanged int32             `json:"unchanged"`
+	Errors    []*ImportRowError `json:"errors"`
+}
+
+type ImportRowError struct {
+	Line    int32   `json:"line"`
+	Field   *string `json:"field,omitempty"`
+	Message string  `json:"message"`
+}
+
+type Location struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Kind      LocationKind `json:"kind"`
+	Address   *string      `json:"address,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+type LocationInput struct {
+	Name    string       `json:"name"`
+	Kind    LocationKind `json:"kind"`
+	Address *string      `json:"address,omitempty"`
+}
+
+type LocationUpdateInput struct {
+	Name    *string       `json:"name,omitempty"`
+	Kind    *LocationKind `json:"kind,omitempty"`
+	Address *string       `json:"address,omitempty"`
+}
+
+type ModelMileage struct {
+	ModelCode      string  `json:"modelCode"`
+	Vehicles       int32   `json:"vehicles"`
+	AverageMileage float64 `json:"averageMileage"`
+}
+
+type Movement struct {
+	ID             string                `json:"id"`
+	VehicleID      string                `json:"vehicleId"`
+	Type           string                `json:"type"`
+	Description    *string               `json:"description,omitempty"`
+	OccurredAt     time.Time             `json:"occurredAt"`
+	Metadata       *string               `json:"metadata,omitempty"`
+	Details        MovementDetails       `json:"details,omitempty"`
+	FromLocationID *string               `json:"fromLocationId,omitempty"`
+	FromLocation   *Location             `json:"fromLocation,omitempty"`
+	ToLocationID   *string               `json:"toLocationId,omitempty"`
+	ToLocation     *Location             `json:"toLocation,omitempty"`
+	FromStatus     *VehicleStatus        `json:"fromStatus,omitempty"`
+	ToStatus       *VehicleStatus        `json:"toStatus,omitempty"`
+	VoidedAt       *time.Time            `json:"voidedAt,omitempty"`
+	VoidedByID     *string               `json:"voidedById,omitempty"`
+	VoidReason     *string               `json:"voidReason,omitempty"`
+	ReversesID     *string               `json:"reversesId,omitempty"`
+	ReversedByID   *string               `json:"reversedById,omitempty"`
+	CorrelationID  *string               `json:"correlationId,omitempty"`
+	CreatedByID    string                `json:"createdById"`
+	CreatedBy      *User                 `json:"createdBy,omitempty"`
+	Vehicle        *Vehicle              `json:"vehicle,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+	History        []*MovementCorrection `json:"history"`
+}
+
+type MovementBucket struct {
+	Start time.Time `json:"start"`
+	Count int32     `json:"count"`
+}
+
+type MovementConnection struct {
+	Edges      []*MovementEdge `json:"edges"`
+	PageInfo   *PageInfo       `json:"pageInfo"`
+	TotalCount int32           `json:"totalCount"`
+}
+
+type MovementCorrection struct {
+	ID         string                   `json:"id"`
+	MovementID string                   `json:"movementId"`
+	Action     MovementCorrectionAction `json:"action"`
+	ActorID    *string                  `json:"actorId,omitempty"`
+	Actor      *User                    `json:"actor,omitempty"`
+	Changes    []*FieldChange           `json:"changes"`
+	Reason     *string                  `json:"reason,omitempty"`
+	CreatedAt  time.Time                `json:"createdAt"`
+}
+
+type MovementEdge struct {
+	Cursor string    `json:"cursor"`
+	Node   *Movement `json:"node"`
+}
+
+type MovementInput struct {
+	VehicleID    string    `json:"vehicleId"`
+	Type         string    `json:"type"`
+	Description  *string   `json:"description,omitempty"`
+	OccurredAt   time.Time `json:"occurredAt"`
+	Metadata     *string   `json:"metadata,omitempty"`
+	ToLocationID *string   `json:"toLocationId,omitempty"`
+}
+
+// JSON Schema that metadata of a movement type must satisfy when a movement is recorded. Supported
+// keywords: type, enum, const, properties, required, additionalProperties (boolean), items,
+// minItems, maxItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+// pattern, plus the annotations title, description, $schema, $comment, default and examples.
+type MovementMetadataSchema struct {
+	Type        string    `json:"type"`
+	Schema      string    `json:"schema"`
+	UpdatedByID *string   `json:"updatedById,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type MovementReportRow struct {
+	Type  string `json:"type"`
+	Count int32  `json:"count"`
+}
+
+type MovementSeries struct {
+	Key     *string           `json:"key,omitempty"`
+	Total   int32             `json:"total"`
+	Buckets []*MovementBucket `json:"buckets"`
+}
+
+// A kind of movement and the rules for recording it. A movement may be recorded on a vehicle whose
+// status is in fromStatuses; it then leaves the vehicle in toStatus, or in the same status when
+// toStatus is null. Built-in types cannot be deleted.
+type MovementType struct {
+	Name           string          `json:"name"`
+	Description    *string         `json:"description,omitempty"`
+	FromStatuses   []VehicleStatus `json:"fromStatuses"`
+	ToStatus       *VehicleStatus  `json:"toStatus,omitempty"`
+	LocationRule   LocationRule    `json:"locationRule"`
+	MetadataSchema *string         `json:"metadataSchema,omitempty"`
+	BuiltIn        bool            `json:"builtIn"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+type MovementTypeInput struct {
+	Name         string          `json:"name"`
+	Description  *string         `json:"description,omitempty"`
+	FromStatuses []VehicleStatus `json:"fromStatuses"`
+	ToStatus     *VehicleStatus  `json:"toStatus,omitempty"`
+	LocationRule *LocationRule   `json:"locationRule,omitempty"`
+}
+
+type MovementTypeUpdateInput struct {
+	Description  *string         `json:"description,omitempty"`
+	FromStatuses []VehicleStatus `json:"fromStatuses"`
+	ToStatus     *VehicleStatus  `json:"toStatus,omitempty"`
+	LocationRule LocationRule    `json:"locationRule"`
+}
+
+type MovementUpdateInput struct {
+	Type        *string    `json:"type,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	OccurredAt  *time.Time `json:"occurredAt,omitempty"`
+	Metadata    *string    `json:"metadata,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Query struct {
+}
+
+type Role struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	BuiltIn     bool      `json:"builtIn"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type SaleDetails struct {
+	Price    *float64 `json:"price,omitempty"`
+	Currency *string  `json:"currency,omitempty"`
+	Buyer    *string  `json:"buyer,omitempty"`
+}
+
+func (SaleDetails) IsMovementDetails() {}
+
+type SearchHighlight struct {
+	Field  string            `json:"field"`
+	Value  string            `json:"value"`
+	Ranges []*HighlightRange `json:"ranges"`
+}
+
+type StatusCount struct {
+	Status VehicleStatus `json:"status"`
+	Count  int32         `json:"count"`
+}
+
+type Subscription struct {
+}
+
+type TractionCount struct {
+	TractionType TractionType `json:"tractionType"`
+	Count        int32        `json:"count"`
+}
+
+type TransferDetails struct {
+	From *string `json:"from,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+func (TransferDetails) IsMovementDetails() {}
+
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Role      *Role     `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	RoleID    int64     `json:"-"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int32       `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
+type Vehicle struct {
+	ID                  string                  `json:"id"`
+	Vin                 string                  `json:"vin"`
+	Name                string                  `json:"name"`
+	ModelCode           string                  `json:"modelCode"`
+	TractionType        TractionType            `json:"tractionType"`
+	ReleaseYear         int32                   `json:"releaseYear"`
+	ReleaseYearMismatch bool                    `json:"releaseYearMismatch"`
+	BatchNumber         string                  `json:"batchNumber"`
+	Color               *string                 `json:"color,omitempty"`
+	Mileage             int32                   `json:"mileage"`
+	Status              VehicleStatus           `json:"status"`
+	Manufacturer        *string                 `json:"manufacturer,omitempty"`
+	PlantCode           *string                 `json:"plantCode,omitempty"`
+	CurrentLocationID   *string                 `json:"currentLocationId,omitempty"`
+	CurrentLocation     *Location               `json:"currentLocation,omitempty"`
+	CreatedAt           time.Time               `json:"createdAt"`
+	UpdatedAt           time.Time               `json:"updatedAt"`
+	DeletedAt           *time.Time              `json:"deletedAt,omitempty"`
+	Movements           *MovementConnection     `json:"movements"`
+	History             *VehicleAuditConnection `json:"history"`
+}
+
+type VehicleAuditConnection struct {
+	Edges      []*VehicleAuditEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int32               `json:"totalCount"`
+}
+
+type VehicleAuditEdge struct {
+	Cursor string             `json:"cursor"`
+	Node   *VehicleAuditEntry `json:"node"`
+}
+
+type VehicleAuditEntry struct {
+	ID        string         `json:"id"`
+	VehicleID string         `json:"vehicleId"`
+	Action    AuditAction    `json:"action"`
+	ActorID   *string        `json:"actorId,omitempty"`
+	Actor     *User          `json:"actor,omitempty"`
+	Changes   []*FieldChange `json:"changes"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type VehicleChange struct {
+	Action    AuditAction `json:"action"`
+	VehicleID string      `json:"vehicleId"`
+	Vehicle   *Vehicle    `json:"vehicle,omitempty"`
+}
+
+type VehicleConnection struct {
+	Edges      []*VehicleEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int32          `json:"totalCount"`
+}
+
+type VehicleEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Vehicle `json:"node"`
+}
+
+type VehicleFilter struct {
+	Status          []VehicleStatus `json:"status,omitempty"`
+	TractionType    []TractionType  `json:"tractionType,omitempty"`
+	ModelCode       *string         `json:"modelCode,omitempty"`
+	ReleaseYearFrom *int32          `json:"releaseYearFrom,omitempty"`
+	ReleaseYearTo   *int32          `json:"releaseYearTo,omitempty"`
+	BatchNumber     *string         `json:"batchNumber,omitempty"`
+	Color           *string         `json:"color,omitempty"`
+	MileageMin      *int32          `json:"mileageMin,omitempty"`
+	MileageMax      *int32          `json:"mileageMax,omitempty"`
+	CreatedAfter    *time.Time      `json:"createdAfter,omitempty"`
+	CreatedBefore   *time.Time      `json:"createdBefore,omitempty"`
+	UpdatedAfter    *time.Time      `json:"updatedAfter,omitempty"`
+	UpdatedBefore   *time.Time      `json:"updatedBefore,omitempty"`
+}
+
+type VehicleInput struct {
+	Vin          string       `json:"vin"`
+	Name         string       `json:"name"`
+	ModelCode    string       `json:"modelCode"`
+	TractionType TractionType `json:"tractionType"`
+	ReleaseYear  *int32       `json:"releaseYear,omitempty"`
+	BatchNumber  string       `json:"batchNumber"`
+	Color        *string      `json:"color,omitempty"`
+	Mileage      *int32       `json:"mileage,omitempty"`
+	Manufacturer *string      `json:"manufacturer,omitempty"`
+	PlantCode    *string      `json:"plantCode,omitempty"`
+	LocationID   *string      `json:"locationId,omitempty"`
+}
+
+type VehicleSearchResult struct {
+	Vehicle    *Vehicle           `json:"vehicle"`
+	Score      float64            `json:"score"`
+	Highlights []*SearchHighlight `json:"highlights"`
+}
+
+type VehicleUpdateInput struct {
+	Name         *string        `json:"name,omitempty"`
+	ModelCode    *string        `json:"modelCode,omitempty"`
+	TractionType *TractionType  `json:"tractionType,omitempty"`
+	ReleaseYear  *int32         `json:"releaseYear,omitempty"`
+	BatchNumber  *string        `json:"batchNumber,omitempty"`
+	Color        *string        `json:"color,omitempty"`
+	Mileage      *int32         `json:"mileage,omitempty"`
+	Status       *VehicleStatus `json:"status,omitempty"`
+	Manufacturer *string        `json:"manufacturer,omitempty"`
+	PlantCode    *string        `json:"plantCode,omitempty"`
+}
+
+// What decodeVin reads from a VIN using the built-in WMI and model-year tables. Unknown parts are
+// null. Position 10 repeats every 30 years, so modelYearCandidates lists every plausible year and
+// modelYear the most likely one.
+type VinDecoding struct {
+	Vin                 string  `json:"vin"`
+	Wmi                 string  `json:"wmi"`
+	Manufacturer        *string `json:"manufacturer,omitempty"`
+	Country             *string `json:"country,omitempty"`
+	Region              *string `json:"region,omitempty"`
+	ModelYear           *int32  `json:"modelYear,omitempty"`
+	ModelYearCandidates []int32 `json:"modelYearCandidates"`
+	PlantCode           string  `json:"plantCode"`
+	SerialNumber        string  `json:"serialNumber"`
+	CheckDigitValid     bool    `json:"checkDigitValid"`
+}
+
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// One event sent to one webhook. body is the exact JSON posted; responseStatus and lastError
+// describe the latest attempt.
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	WebhookID      string                `json:"webhookId"`
+	EventID        string                `json:"eventId"`
+	Event          string                `json:"event"`
+	Body           string                `json:"body"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time            `json:"lastAttemptAt,omitempty"`
+	ResponseStatus *int32                `json:"responseStatus,omitempty"`
+	LastError      *string               `json:"lastError,omitempty"`
+	CreatedAt      time.Time             `json:"createdAt"`
+}
+
+type WebhookDeliveryConnection struct {
+	Edges      []*WebhookDeliveryEdge `json:"edges"`
+	PageInfo   *PageInfo              `json:"pageInfo"`
+	TotalCount int32                  `json:"totalCount"`
+}
+
+type WebhookDeliveryEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *WebhookDelivery `json:"node"`
+}
+
+type WebhookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret *string  `json:"secret,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type WebhookUpdateInput struct {
+	URL    *string  `json:"url,omitempty"`
+	Events []string `json:"events,omitempty"`
+	Secret *string  `json:"secret,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "CREATE"
+	AuditActionUpdate  AuditAction = "UPDATE"
+	AuditActionDelete  AuditAction = "DELETE"
+	AuditActionRestore AuditAction = "RESTORE"
+	AuditActionPurge   AuditAction = "PURGE"
+)
+
+var AllAuditAction = []AuditAction{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionDelete,
+	AuditActionRestore,
+	AuditActionPurge,
+}
+
+func (e AuditAction) IsValid() bool {
+	switch e {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore, AuditActionPurge:
+		return true
+	}
+	return false
+}
+
+func (e AuditAction) String() string {
+	return string(e)
+}
+
+func (e *AuditAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AuditAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AuditAction", str)
+	}
+	return nil
+}
+
+func (e AuditAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AuditAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AuditAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DomainEventType string
+
+const (
+	DomainEventTypeVehicleCreated   DomainEventType = "VEHICLE_CREATED"
+	DomainEventTypeVehicleUpdated   DomainEventType = "VEHICLE_UPDATED"
+	DomainEventTypeVehicleDeleted   DomainEventType = "VEHICLE_DELETED"
+	DomainEventTypeVehicleRestored  DomainEventType = "VEHICLE_RESTORED"
+	DomainEventTypeVehiclePurged    DomainEventType = "VEHICLE_PURGED"
+	DomainEventTypeMovementCreated  DomainEventType = "MOVEMENT_CREATED"
+	DomainEventTypeMovementUpdated  DomainEventType = "MOVEMENT_UPDATED"
+	DomainEventTypeMovementVoided   DomainEventType = "MOVEMENT_VOIDED"
+	DomainEventTypeMovementReversed DomainEventType = "MOVEMENT_REVERSED"
+	DomainEventTypeUserRoleChanged  DomainEventType = "USER_ROLE_CHANGED"
+)
+
+var AllDomainEventType = []DomainEventType{
+	DomainEventTypeVehicleCreated,
+	DomainEventTypeVehicleUpdated,
+	DomainEventTypeVehicleDeleted,
+	DomainEventTypeVehicleRestored,
+	DomainEventTypeVehiclePurged,
+	DomainEventTypeMovementCreated,
+	DomainEventTypeMovementUpdated,
+	DomainEventTypeMovementVoided,
+	DomainEventTypeMovementReversed,
+	DomainEventTypeUserRoleChanged,
+}
+
+func (e DomainEventType) IsValid() bool {
+	switch e {
+	case DomainEventTypeVehicleCreated, DomainEventTypeVehicleUpdated, DomainEventTypeVehicleDeleted, DomainEventTypeVehicleRestored, DomainEventTypeVehiclePurged, DomainEventTypeMovementCreated, DomainEventTypeMovementUpdated, DomainEventTypeMovementVoided, DomainEventTypeMovementReversed, DomainEventTypeUserRoleChanged:
+		return true
+	}
+	return false
+}
+
+func (e DomainEventType) String() string {
+	return string(e)
+}
+
+func (e *DomainEventType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DomainEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DomainEventType", str)
+	}
+	return nil
+}
+
+func (e DomainEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DomainEventType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DomainEventType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "CSV"
+	ImportFormatJSON ImportFormat = "JSON"
+)
+
+var AllImportFormat = []ImportFormat{
+	ImportFormatCSV,
+	ImportFormatJSON,
+}
+
+func (e ImportFormat) IsValid() bool {
+	switch e {
+	case ImportFormatCSV, ImportFormatJSON:
+		return true
+	}
+	return false
+}
+
+func (e ImportFormat) String() string {
+	return string(e)
+}
+
+func (e *ImportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
+	}
+	return nil
+}
+
+func (e ImportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportMode string
+
+const (
+	ImportModeInsert ImportMode = "INSERT"
+	ImportModeUpsert ImportMode = "UPSERT"
+)
+
+var AllImportMode = []ImportMode{
+	ImportModeInsert,
+	ImportModeUpsert,
+}
+
+func (e ImportMode) IsValid() bool {
+	switch e {
+	case ImportModeInsert, ImportModeUpsert:
+		return true
+	}
+	return false
+}
+
+func (e ImportMode) String() string {
+	return string(e)
+}
+
+func (e *ImportMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportMode", str)
+	}
+	return nil
+}
+
+func (e ImportMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LocationKind string
+
+const (
+	LocationKindYard   LocationKind = "YARD"
+	LocationKindDealer LocationKind = "DEALER"
+	LocationKindPlant  LocationKind = "PLANT"
+)
+
+var AllLocationKind = []LocationKind{
+	LocationKindYard,
+	LocationKindDealer,
+	LocationKindPlant,
+}
+
+func (e LocationKind) IsValid() bool {
+	switch e {
+	case LocationKindYard, LocationKindDealer, LocationKindPlant:
+		return true
+	}
+	return false
+}
+
+func (e LocationKind) String() string {
+	return string(e)
+}
+
+func (e *LocationKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LocationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LocationKind", str)
+	}
+	return nil
+}
+
+func (e LocationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LocationKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
 }
 
-type Movement struct {
-	ID          string       `json:"id"`
-	VehicleID   string       `json:"vehicleId"`
-	Type        MovementType `json:"type"`
-	Description *string      `json:"description,omitempty"`
-	OccurredAt  time.Time    `json:"occurredAt"`
-	Metadata    *string      `json:"metadata,omitempty"`
-	CreatedBy   string       `json:"createdBy"`
-	CreatedAt   time.Time    `json:"createdAt"`
+func (e LocationKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
 
-type MovementInput struct {
-	VehicleID   string       `json:"vehicleId"`
-	Type        MovementType `json:"type"`
-	Description *string      `json:"description,omitempty"`
-	OccurredAt  time.Time    `json:"occurredAt"`
-	Metadata    *string      `json:"metadata,omitempty"`
+type LocationRule string
+
+const (
+	LocationRuleNone     LocationRule = "NONE"
+	LocationRuleOptional LocationRule = "OPTIONAL"
+	LocationRuleRequired LocationRule = "REQUIRED"
+)
+
+var AllLocationRule = []LocationRule{
+	LocationRuleNone,
+	LocationRuleOptional,
+	LocationRuleRequired,
 }
 
-type MovementReportRow struct {
-	Type  MovementType `json:"type"`
-	Count int32        `json:"count"`
+func (e LocationRule) IsValid() bool {
+	switch e {
+	case LocationRuleNone, LocationRuleOptional, LocationRuleRequired:
+		return true
+	}
+	return false
 }
 
-type Mutation struct {
+func (e LocationRule) String() string {
+	return string(e)
 }
 
-type Query struct {
+func (e *LocationRule) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LocationRule(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LocationRule", str)
+	}
+	return nil
 }
 
-type Role struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"createdAt"`
+func (e LocationRule) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-type User struct {
-	ID        string    `json:"id"`
-	Email     string    `json:"email"`
-	Role      *Role     `json:"role"`
-	CreatedAt time.Time `json:"createdAt"`
+func (e *LocationRule) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
 }
 
-type Vehicle struct {
-	ID           string        `json:"id"`
-	Vin          string        `json:"vin"`
-	Name         string        `json:"name"`
-	ModelCode    string        `json:"modelCode"`
-	TractionType TractionType  `json:"tractionType"`
-	ReleaseYear  int32         `json:"releaseYear"`
-	BatchNumber  string        `json:"batchNumber"`
-	Color        *string       `json:"color,omitempty"`
-	Mileage      int32         `json:"mileage"`
-	Status       VehicleStatus `json:"status"`
-	CreatedAt    time.Time     `json:"createdAt"`
-	UpdatedAt    time.Time     `json:"updatedAt"`
-	Movements    []*Movement   `json:"movements"`
+func (e LocationRule) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
 
-type VehicleInput struct {
-	Vin          string         `json:"vin"`
-	Name         string         `json:"name"`
-	ModelCode    string         `json:"modelCode"`
-	TractionType TractionType   `json:"tractionType"`
-	ReleaseYear  int32          `json:"releaseYear"`
-	BatchNumber  string         `json:"batchNumber"`
-	Color        *string        `json:"color,omitempty"`
-	Mileage      *int32         `json:"mileage,omitempty"`
-	Status       *VehicleStatus `json:"status,omitempty"`
+type MovementCorrectionAction string
+
+const (
+	MovementCorrectionActionUpdate  MovementCorrectionAction = "UPDATE"
+	MovementCorrectionActionVoid    MovementCorrectionAction = "VOID"
+	MovementCorrectionActionReverse MovementCorrectionAction = "REVERSE"
+)
+
+var AllMovementCorrectionAction = []MovementCorrectionAction{
+	MovementCorrectionActionUpdate,
+	MovementCorrectionActionVoid,
+	MovementCorrectionActionReverse,
 }
 
-type VehicleUpdateInput struct {
-	Name         *string        `json:"name,omitempty"`
-	ModelCode    *string        `json:"modelCode,omitempty"`
-	TractionType *TractionType  `json:"tractionType,omitempty"`
-	ReleaseYear  *int32         `json:"releaseYear,omitempty"`
-	BatchNumber  *string        `json:"batchNumber,omitempty"`
-	Color        *string        `json:"color,omitempty"`
-	Mileage      *int32         `json:"mileage,omitempty"`
-	Status       *VehicleStatus `json:"status,omitempty"`
+func (e MovementCorrectionAction) IsValid() bool {
+	switch e {
+	case MovementCorrectionActionUpdate, MovementCorrectionActionVoid, MovementCorrectionActionReverse:
+		return true
+	}
+	return false
+}
+
+func (e MovementCorrectionAction) String() string {
+	return string(e)
+}
+
+func (e *MovementCorrectionAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MovementCorrectionAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MovementCorrectionAction", str)
+	}
+	return nil
+}
+
+func (e MovementCorrectionAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MovementCorrectionAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MovementCorrectionAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MovementGroupBy string
+
+const (
+	MovementGroupByType         MovementGroupBy = "TYPE"
+	MovementGroupByModelCode    MovementGroupBy = "MODEL_CODE"
+	MovementGroupByBatchNumber  MovementGroupBy = "BATCH_NUMBER"
+	MovementGroupByTractionType MovementGroupBy = "TRACTION_TYPE"
+)
+
+var AllMovementGroupBy = []MovementGroupBy{
+	MovementGroupByType,
+	MovementGroupByModelCode,
+	MovementGroupByBatchNumber,
+	MovementGroupByTractionType,
+}
+
+func (e MovementGroupBy) IsValid() bool {
+	switch e {
+	case MovementGroupByType, MovementGroupByModelCode, MovementGroupByBatchNumber, MovementGroupByTractionType:
+		return true
+	}
+	return false
+}
+
+func (e MovementGroupBy) String() string {
+	return string(e)
+}
+
+func (e *MovementGroupBy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MovementGroupBy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MovementGroupBy", str)
+	}
+	return nil
+}
+
+func (e MovementGroupBy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MovementGroupBy) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MovementGroupBy) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportInterval string
+
+const (
+	ReportIntervalDay   ReportInterval = "DAY"
+	ReportIntervalWeek  ReportInterval = "WEEK"
+	ReportIntervalMonth ReportInterval = "MONTH"
+)
+
+var AllReportInterval = []ReportInterval{
+	ReportIntervalDay,
+	ReportIntervalWeek,
+	ReportIntervalMonth,
+}
+
+func (e ReportInterval) IsValid() bool {
+	switch e {
+	case ReportIntervalDay, ReportIntervalWeek, ReportIntervalMonth:
+		return true
+	}
+	return false
+}
+
+func (e ReportInterval) String() string {
+	return string(e)
+}
+
+func (e *ReportInterval) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportInterval", str)
+	}
+	return nil
+}
+
+func (e ReportInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportInterval) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportInterval) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
 
-type MovementType string
+type SortDirection string
 
 const (
-	MovementTypeSale         MovementType = "SALE"
-	MovementTypeDefect       MovementType = "DEFECT"
-	MovementTypeDiscontinued MovementType = "DISCONTINUED"
-	MovementTypeTransfer     MovementType = "TRANSFER"
-	MovementTypeReturn       MovementType = "RETURN"
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
 )
 
-var AllMovementType = []MovementType{
-	MovementTypeSale,
-	MovementTypeDefect,
-	MovementTypeDiscontinued,
-	MovementTypeTransfer,
-	MovementTypeReturn,
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
 }
 
-func (e MovementType) IsValid() bool {
+func (e SortDirection) IsValid() bool {
 	switch e {
-	case MovementTypeSale, MovementTypeDefect, MovementTypeDiscontinued, MovementTypeTransfer, MovementTypeReturn:
+	case SortDirectionAsc, SortDirectionDesc:
 		return true
 	}
 	return false
 }
 
-func (e MovementType) String() string {
+func (e SortDirection) String() string {
 	return string(e)
 }
 
-func (e *MovementType) UnmarshalGQL(v any) error {
+func (e *SortDirection) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = MovementType(str)
+	*e = SortDirection(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid MovementType", str)
+		return fmt.Errorf("%s is not a valid SortDirection", str)
 	}
 	return nil
 }
 
-func (e MovementType) MarshalGQL(w io.Writer) {
+func (e SortDirection) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *MovementType) UnmarshalJSON(b []byte) error {
+func (e *SortDirection) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -152,7 +1142,7 @@ func (e *MovementType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e MovementType) MarshalJSON() ([]byte, error) {
+func (e SortDirection) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -217,23 +1207,90 @@ func (e TractionType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type VehicleSort string
+
+const (
+	VehicleSortCreatedAt   VehicleSort = "CREATED_AT"
+	VehicleSortUpdatedAt   VehicleSort = "UPDATED_AT"
+	VehicleSortName        VehicleSort = "NAME"
+	VehicleSortVin         VehicleSort = "VIN"
+	VehicleSortModelCode   VehicleSort = "MODEL_CODE"
+	VehicleSortReleaseYear VehicleSort = "RELEASE_YEAR"
+	VehicleSortMileage     VehicleSort = "MILEAGE"
+)
+
+var AllVehicleSort = []VehicleSort{
+	VehicleSortCreatedAt,
+	VehicleSortUpdatedAt,
+	VehicleSortName,
+	VehicleSortVin,
+	VehicleSortModelCode,
+	VehicleSortReleaseYear,
+	VehicleSortMileage,
+}
+
+func (e VehicleSort) IsValid() bool {
+	switch e {
+	case VehicleSortCreatedAt, VehicleSortUpdatedAt, VehicleSortName, VehicleSortVin, VehicleSortModelCode, VehicleSortReleaseYear, VehicleSortMileage:
+		return true
+	}
+	return false
+}
+
+func (e VehicleSort) String() string {
+	return string(e)
+}
+
+func (e *VehicleSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = VehicleSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid VehicleSort", str)
+	}
+	return nil
+}
+
+func (e VehicleSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *VehicleSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e VehicleSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type VehicleStatus string
 
 const (
 	VehicleStatusActive       VehicleStatus = "ACTIVE"
 	VehicleStatusInactive     VehicleStatus = "INACTIVE"
+	VehicleStatusSold         VehicleStatus = "SOLD"
 	VehicleStatusDiscontinued VehicleStatus = "DISCONTINUED"
 )
 
 var AllVehicleStatus = []VehicleStatus{
 	VehicleStatusActive,
 	VehicleStatusInactive,
+	VehicleStatusSold,
 	VehicleStatusDiscontinued,
 }
 
 func (e VehicleStatus) IsValid() bool {
 	switch e {
-	case VehicleStatusActive, VehicleStatusInactive, VehicleStatusDiscontinued:
+	case VehicleStatusActive, VehicleStatusInactive, VehicleStatusSold, VehicleStatusDiscontinued:
 		return true
 	}
 	return false
@@ -273,3 +1330,60 @@ func (e VehicleStatus) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusSucceeded,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -3,7 +3,10 @@
 package graph
 
 import (
+	"context"
+
 	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	httpx "github.com/Kenfoxfire/Gear-Core-app/internal/http"
 	"github.com/go-pg/pg/v10"
 )
 
@@ -11,5 +14,20 @@ type Resolver struct {
 	DB        *pg.DB
 	Repos     *domain.Repos
 	Auth      *domain.AuthService
+	Authz     *domain.Authorizer
+	Notifier  *domain.Notifier
+	Stats     *domain.FleetStatsCache
 	JWTSecret []byte
 }
+
+// sessionLive reports whether the caller's session is still valid. A websocket is authenticated
+// once when it connects, so subscriptions check this before every event and end after a logout.
+func (r *Resolver) sessionLive(ctx context.Context) bool {
+	uid, _, _ := httpx.UserFrom(ctx)
+	sid, ok := httpx.SessionFrom(ctx)
+	if !ok {
+		return false
+	}
+	_, err := r.Auth.SessionRole(ctx, sid, uid)
+	return err == nil
+}
@@ -7,21 +7,126 @@ package graph
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"log"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/graph/loaders"
 	"github.com/Kenfoxfire/Gear-Core-app/internal/graph/model"
 	httpx "github.com/Kenfoxfire/Gear-Core-app/internal/http"
 )
 
+// Vehicles is the resolver for the vehicles field.
+func (r *batchResolver) Vehicles(ctx context.Context, obj *model.Batch, first *int32, after *string) (*model.VehicleConnection, error) {
+	page, err := pageArgs(ctx, first, after, 20)
+	if err != nil {
+		return nil, err
+	}
+	vehicles, err := r.Repos.ListVehicles(ctx, domain.VehicleFilter{BatchNumber: &obj.Number}, domain.VehicleSort{Field: domain.SortVIN}, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapVehicleConnection(vehicles, page.After), nil
+}
+
+// FromLocation is the resolver for the fromLocation field.
+func (r *movementResolver) FromLocation(ctx context.Context, obj *model.Movement) (*model.Location, error) {
+	return r.loadLocation(ctx, "fromLocationId", obj.FromLocationID)
+}
+
+// ToLocation is the resolver for the toLocation field.
+func (r *movementResolver) ToLocation(ctx context.Context, obj *model.Movement) (*model.Location, error) {
+	return r.loadLocation(ctx, "toLocationId", obj.ToLocationID)
+}
+
+// ReversedByID is the resolver for the reversedById field.
+func (r *movementResolver) ReversedByID(ctx context.Context, obj *model.Movement) (*string, error) {
+	id, err := parseID("id", obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	rev, err := loaders.For(ctx, r.Repos).ReversalOf.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return optID(rev), nil
+}
+
+// CreatedBy is the resolver for the createdBy field.
+func (r *movementResolver) CreatedBy(ctx context.Context, obj *model.Movement) (*model.User, error) {
+	id, err := parseID("createdById", obj.CreatedByID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := loaders.For(ctx, r.Repos).UserByID.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapUser(u), nil
+}
+
+// Vehicle is the resolver for the vehicle field.
+func (r *movementResolver) Vehicle(ctx context.Context, obj *model.Movement) (*model.Vehicle, error) {
+	id, err := parseID("vehicleId", obj.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := loaders.For(ctx, r.Repos).VehicleByID.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if v.DeletedAt != nil {
+		_, role, _ := httpx.UserFrom(ctx)
+		ok, err := r.Authz.Can(ctx, role, domain.PermVehicleReadDeleted)
+		if err != nil || !ok {
+			return nil, err
+		}
+	}
+	return mapVehicle(v), nil
+}
+
+// History is the resolver for the history field.
+func (r *movementResolver) History(ctx context.Context, obj *model.Movement) ([]*model.MovementCorrection, error) {
+	id, err := parseID("id", obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.Repos.ListMovementAudit(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.MovementCorrection, 0, len(items))
+	for _, a := range items {
+		out = append(out, mapMovementCorrection(a))
+	}
+	return out, nil
+}
+
+// Actor is the resolver for the actor field.
+func (r *movementCorrectionResolver) Actor(ctx context.Context, obj *model.MovementCorrection) (*model.User, error) {
+	if obj.ActorID == nil {
+		return nil, nil
+	}
+	id, err := parseID("actorId", *obj.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := loaders.For(ctx, r.Repos).UserByID.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapUser(u), nil
+}
+
 // Signup is the resolver for the signup field.
 func (r *mutationResolver) Signup(ctx context.Context, email string, password string) (*model.AuthPayload, error) {
 	u, tok, err := r.Auth.SignupViewer(ctx, email, password)
 	if err != nil {
 		return nil, err
 	}
-	return &model.AuthPayload{Token: tok, User: mapUser(u)}, nil
+	return mapAuthPayload(u, tok), nil
 }
 
 // Login is the resolver for the login field.
@@ -31,154 +136,539 @@ func (r *mutationResolver) Login(ctx context.Context, email string, password str
 		return nil, err
 	}
 
-	return &model.AuthPayload{Token: tok, User: mapUser(u)}, nil
+	return mapAuthPayload(u, tok), nil
 }
 
-// CreateVehicle is the resolver for the createVehicle field.
-func (r *mutationResolver) CreateVehicle(ctx context.Context, input model.VehicleInput) (*model.Vehicle, error) {
-	_, role, ok := httpx.UserFrom(ctx)
-	if !ok || role == "" || role == "Viewer" {
-		return nil, httpx.ErrForbidden
+// RefreshToken is the resolver for the refreshToken field.
+func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string) (*model.AuthPayload, error) {
+	u, tok, err := r.Auth.Refresh(ctx, refreshToken)
+	if err != nil {
+		return nil, err
 	}
-	v, _ := r.Repos.GetVehicleByVin(ctx, input.Vin)
-	if v != nil {
-		return nil, fmt.Errorf("vehicle with vin %s already exists", input.Vin)
+	return mapAuthPayload(u, tok), nil
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context) (bool, error) {
+	sid, _ := httpx.SessionFrom(ctx)
+	if err := r.Auth.Logout(ctx, sid); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	v = &domain.Vehicle{
+// LogoutAllSessions is the resolver for the logoutAllSessions field.
+func (r *mutationResolver) LogoutAllSessions(ctx context.Context) (bool, error) {
+	uid, _, _ := httpx.UserFrom(ctx)
+	if err := r.Auth.LogoutAll(ctx, uid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateVehicle is the resolver for the createVehicle field.
+func (r *mutationResolver) CreateVehicle(ctx context.Context, input model.VehicleInput) (*model.Vehicle, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	var err error
+	v := &domain.Vehicle{
 		VIN: input.Vin, Name: input.Name, ModelCode: input.ModelCode,
-		TractionType: string(input.TractionType), ReleaseYear: int(input.ReleaseYear),
+		TractionType: string(input.TractionType), ReleaseYear: ptrInt32ToInt(input.ReleaseYear, 0),
 		BatchNumber: input.BatchNumber, Color: ptrStr(input.Color), Mileage: ptrInt32ToInt(input.Mileage, 0),
-		Status: string(*input.Status),
+		Manufacturer: ptrStr(input.Manufacturer), PlantCode: ptrStr(input.PlantCode),
 	}
-	v, err := r.Repos.CreateVehicle(ctx, v)
-	if err != nil {
+	if v.CurrentLocationID, err = parseOptID("input.locationId", input.LocationID); err != nil {
 		return nil, err
 	}
+	v, err = r.Repos.CreateVehicle(ctx, v, userID)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
 	return mapVehicle(v), nil
 }
 
 // UpdateVehicle is the resolver for the updateVehicle field.
 func (r *mutationResolver) UpdateVehicle(ctx context.Context, id string, input model.VehicleUpdateInput) (*model.Vehicle, error) {
-	v, err := r.Repos.GetVehicleByID(ctx, parseID(id))
+	userID, _, _ := httpx.UserFrom(ctx)
+	vid, err := parseID("id", id)
 	if err != nil {
-		if err.Error() == "pg: no rows in result set" {
-			return nil, fmt.Errorf("vehicle with id %s not found", id)
-		}
 		return nil, err
 	}
-
-	if input.Name != nil {
-		v.Name = *input.Name
+	if input.Status != nil {
+		return nil, apperr.Invalid("input.status", "cannot be set directly; record a movement instead")
 	}
-	if input.ModelCode != nil {
-		v.ModelCode = *input.ModelCode
+	c := domain.VehicleChanges{
+		Name: input.Name, ModelCode: input.ModelCode, BatchNumber: input.BatchNumber, Color: input.Color,
+		Manufacturer: input.Manufacturer, PlantCode: input.PlantCode,
 	}
 	if input.TractionType != nil {
-		v.TractionType = string(*input.TractionType)
+		t := string(*input.TractionType)
+		c.TractionType = &t
 	}
 	if input.ReleaseYear != nil {
-		v.ReleaseYear = int(*input.ReleaseYear)
-	}
-	if input.BatchNumber != nil {
-		v.BatchNumber = *input.BatchNumber
-	}
-	if input.Color != nil {
-		v.Color = *input.Color
+		y := int(*input.ReleaseYear)
+		c.ReleaseYear = &y
 	}
 	if input.Mileage != nil {
-		v.Mileage = ptrInt32ToInt(input.Mileage, 0)
-	}
-	if input.Status != nil {
-		v.Status = string(*input.Status)
+		m := int(*input.Mileage)
+		c.Mileage = &m
 	}
-	updatedVehicle, err := r.Repos.UpdateVehicle(ctx, v)
+	v, err := r.Repos.UpdateVehicle(ctx, vid, c, userID)
 	if err != nil {
-		return nil, err
+		return nil, underArg("input", err)
 	}
-	return mapVehicle(updatedVehicle), nil
+	return mapVehicle(v), nil
 }
 
 // DeleteVehicle is the resolver for the deleteVehicle field.
 func (r *mutationResolver) DeleteVehicle(ctx context.Context, id string) (bool, error) {
-	_, role, ok := httpx.UserFrom(ctx)
-	if !ok || role == "" || role != "Admin" {
-		return false, httpx.ErrForbidden
+	userID, _, _ := httpx.UserFrom(ctx)
+	vid, err := parseID("id", id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.Repos.DeleteVehicle(ctx, vid, userID); err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	v, err := r.Repos.GetVehicleByID(ctx, parseID(id))
+// RestoreVehicle is the resolver for the restoreVehicle field.
+func (r *mutationResolver) RestoreVehicle(ctx context.Context, id string) (*model.Vehicle, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	vid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.Repos.RestoreVehicle(ctx, vid, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapVehicle(v), nil
+}
 
-	if err != nil || v.ID == 0 {
-		return false, fmt.Errorf("vehicle with id %s not found", id)
+// UpdateVehiclesInBatch is the resolver for the updateVehiclesInBatch field.
+func (r *mutationResolver) UpdateVehiclesInBatch(ctx context.Context, batchNumber string, input model.BatchVehicleUpdateInput) ([]*model.Vehicle, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	c := domain.BatchVehicleChanges{
+		ModelCode: input.ModelCode, BatchNumber: input.BatchNumber, Color: input.Color,
+		Manufacturer: input.Manufacturer, PlantCode: input.PlantCode,
+	}
+	if input.TractionType != nil {
+		t := string(*input.TractionType)
+		c.TractionType = &t
 	}
-	err = r.Repos.DeleteVehicle(ctx, v.ID)
+	if input.ReleaseYear != nil {
+		y := int(*input.ReleaseYear)
+		c.ReleaseYear = &y
+	}
+	vs, err := r.Repos.UpdateVehiclesInBatch(ctx, batchNumber, c, userID)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	out := make([]*model.Vehicle, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, mapVehicle(v))
+	}
+	return out, nil
+}
+
+// PurgeVehicle is the resolver for the purgeVehicle field.
+func (r *mutationResolver) PurgeVehicle(ctx context.Context, id string) (bool, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	vid, err := parseID("id", id)
 	if err != nil {
 		return false, err
 	}
+	if err := r.Repos.PurgeVehicle(ctx, vid, userID); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
+// ImportVehicles is the resolver for the importVehicles field.
+func (r *mutationResolver) ImportVehicles(ctx context.Context, upload graphql.Upload, format model.ImportFormat, mode *model.ImportMode, dryRun *bool) (*model.ImportResult, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+
+	var rows []domain.ImportRow
+	var fileErrs []domain.ImportError
+	switch format {
+	case model.ImportFormatCSV:
+		rows, fileErrs = domain.ParseVehiclesCSV(upload.File)
+	case model.ImportFormatJSON:
+		rows, fileErrs = domain.ParseVehiclesJSON(upload.File)
+	}
+	isDryRun := dryRun != nil && *dryRun
+	if len(fileErrs) > 0 {
+		return mapImportResult(&domain.ImportResult{DryRun: isDryRun, Total: len(rows), Errors: fileErrs}), nil
+	}
+
+	upsert := mode != nil && *mode == model.ImportModeUpsert
+	res, err := r.Repos.ImportVehicles(ctx, rows, upsert, isDryRun, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapImportResult(res), nil
+}
+
 // CreateMovement is the resolver for the createMovement field.
 func (r *mutationResolver) CreateMovement(ctx context.Context, input model.MovementInput) (*model.Movement, error) {
-	userID, role, ok := httpx.UserFrom(ctx)
-	if !ok || role == "" || role == "Viewer" {
-		return nil, httpx.ErrForbidden
+	userID, _, _ := httpx.UserFrom(ctx)
+
+	vid, err := parseID("input.vehicleId", input.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := r.Repos.GetVehicleByID(ctx, vid)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata map[string]any
+	if input.Metadata != nil {
+		if err := json.Unmarshal([]byte(*input.Metadata), &metadata); err != nil {
+			return nil, apperr.Invalid("input.metadata", "is not valid JSON: %v", err)
+		}
+	}
+
+	toLocationID, err := parseOptID("input.toLocationId", input.ToLocationID)
+	if err != nil {
+		return nil, err
 	}
 
-	v, err := r.Repos.GetVehicleByID(ctx, parseID(input.VehicleID))
-	if err != nil || v.ID == 0 {
-		return nil, fmt.Errorf("vehicle with id %s not found", input.VehicleID)
+	m := &domain.Movement{
+		VehicleID:    v.ID,
+		Vehicle:      v,
+		Type:         input.Type,
+		Description:  ptrStr(input.Description),
+		Metadata:     metadata,
+		CreatedBy:    userID,
+		CreatedAt:    time.Now(),
+		OccurredAt:   input.OccurredAt,
+		ToLocationID: toLocationID,
+	}
+	if _, err := r.Repos.RecordMovement(ctx, m); err != nil {
+		return nil, underArg("input", err)
 	}
+	return mapMovement(m), nil
+}
+
+// ApplyMovementToBatch is the resolver for the applyMovementToBatch field.
+func (r *mutationResolver) ApplyMovementToBatch(ctx context.Context, batchNumber string, input model.BatchMovementInput) (*model.BatchMovementResult, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
 
 	var metadata map[string]any
 	if input.Metadata != nil {
 		if err := json.Unmarshal([]byte(*input.Metadata), &metadata); err != nil {
-			return nil, fmt.Errorf("invalid metadata: %w", err)
+			return nil, apperr.Invalid("input.metadata", "is not valid JSON: %v", err)
 		}
 	}
+	toLocationID, err := parseOptID("input.toLocationId", input.ToLocationID)
+	if err != nil {
+		return nil, err
+	}
 
-	m, err := r.Repos.CreateMovement(ctx, &domain.Movement{
-		VehicleID:   v.ID,
-		Vehicle:     v,
-		Type:        string(input.Type),
-		Description: *input.Description,
-		Metadata:    metadata,
-		CreatedBy:   userID,
-		CreatedAt:   time.Now(),
-		OccurredAt:  input.OccurredAt,
-	})
+	m := &domain.Movement{
+		Type:         input.Type,
+		Description:  ptrStr(input.Description),
+		Metadata:     metadata,
+		CreatedBy:    userID,
+		CreatedAt:    time.Now(),
+		OccurredAt:   input.OccurredAt,
+		ToLocationID: toLocationID,
+	}
+	correlationID, ms, err := r.Repos.ApplyMovementToBatch(ctx, batchNumber, m)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	out := &model.BatchMovementResult{CorrelationID: correlationID, Movements: make([]*model.Movement, 0, len(ms))}
+	for _, m := range ms {
+		out.Movements = append(out.Movements, mapMovement(m))
+	}
+	return out, nil
+}
+
+// UpdateMovement is the resolver for the updateMovement field.
+func (r *mutationResolver) UpdateMovement(ctx context.Context, id string, input model.MovementUpdateInput) (*model.Movement, error) {
+	userID, role, _ := httpx.UserFrom(ctx)
+	mid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	c := domain.MovementChanges{Type: input.Type, Description: input.Description, OccurredAt: input.OccurredAt}
+	if input.Metadata != nil {
+		if err := json.Unmarshal([]byte(*input.Metadata), &c.Metadata); err != nil {
+			return nil, apperr.Invalid("input.metadata", "is not valid JSON: %v", err)
+		}
+	}
+	late, err := r.Authz.Can(ctx, role, domain.PermMovementCorrect)
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.Repos.UpdateMovement(ctx, mid, c, userID, late)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapMovement(m), nil
+}
+
+// VoidMovement is the resolver for the voidMovement field.
+func (r *mutationResolver) VoidMovement(ctx context.Context, id string, reason string) (*model.Movement, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	mid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.Repos.VoidMovement(ctx, mid, reason, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapMovement(m), nil
+}
+
+// ReverseMovement is the resolver for the reverseMovement field.
+func (r *mutationResolver) ReverseMovement(ctx context.Context, id string, reason string) (*model.Movement, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	mid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	m, err := r.Repos.ReverseMovement(ctx, mid, reason, userID)
 	if err != nil {
 		return nil, err
 	}
 	return mapMovement(m), nil
 }
 
+// SetMovementMetadataSchema is the resolver for the setMovementMetadataSchema field.
+func (r *mutationResolver) SetMovementMetadataSchema(ctx context.Context, typeArg string, schema string) (*model.MovementMetadataSchema, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(schema), &doc); err != nil {
+		return nil, apperr.Invalid("schema", "must be a JSON object: %v", err)
+	}
+	t, err := r.Repos.SetMovementSchema(ctx, typeArg, doc, userID)
+	if err != nil {
+		return nil, underArg("schema", err)
+	}
+	return mapMovementSchema(t), nil
+}
+
+// DeleteMovementMetadataSchema is the resolver for the deleteMovementMetadataSchema field.
+func (r *mutationResolver) DeleteMovementMetadataSchema(ctx context.Context, typeArg string) (bool, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	if err := r.Repos.DeleteMovementSchema(ctx, typeArg, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateMovementType is the resolver for the createMovementType field.
+func (r *mutationResolver) CreateMovementType(ctx context.Context, input model.MovementTypeInput) (*model.MovementType, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	t := &domain.MovementType{
+		Name: input.Name, Description: ptrStr(input.Description), FromStatuses: vehicleStatuses(input.FromStatuses),
+		LocationRule: domain.LocationNone, UpdatedBy: userID,
+	}
+	if input.ToStatus != nil {
+		t.ToStatus = string(*input.ToStatus)
+	}
+	if input.LocationRule != nil {
+		t.LocationRule = string(*input.LocationRule)
+	}
+	t, err := r.Repos.CreateMovementType(ctx, t)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapMovementType(t), nil
+}
+
+// UpdateMovementType is the resolver for the updateMovementType field.
+func (r *mutationResolver) UpdateMovementType(ctx context.Context, name string, input model.MovementTypeUpdateInput) (*model.MovementType, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	t := &domain.MovementType{
+		Name: name, Description: ptrStr(input.Description), FromStatuses: vehicleStatuses(input.FromStatuses),
+		LocationRule: string(input.LocationRule), UpdatedBy: userID,
+	}
+	if input.ToStatus != nil {
+		t.ToStatus = string(*input.ToStatus)
+	}
+	t, err := r.Repos.UpdateMovementType(ctx, t)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapMovementType(t), nil
+}
+
+// DeleteMovementType is the resolver for the deleteMovementType field.
+func (r *mutationResolver) DeleteMovementType(ctx context.Context, name string) (bool, error) {
+	if err := r.Repos.DeleteMovementType(ctx, name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateLocation is the resolver for the createLocation field.
+func (r *mutationResolver) CreateLocation(ctx context.Context, input model.LocationInput) (*model.Location, error) {
+	l := &domain.Location{Name: input.Name, Kind: string(input.Kind), Address: ptrStr(input.Address)}
+	l, err := r.Repos.CreateLocation(ctx, l)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapLocation(l), nil
+}
+
+// UpdateLocation is the resolver for the updateLocation field.
+func (r *mutationResolver) UpdateLocation(ctx context.Context, id string, input model.LocationUpdateInput) (*model.Location, error) {
+	lid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	l, err := r.Repos.GetLocationByID(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		l.Name = *input.Name
+	}
+	if input.Kind != nil {
+		l.Kind = string(*input.Kind)
+	}
+	if input.Address != nil {
+		l.Address = *input.Address
+	}
+	if l, err = r.Repos.UpdateLocation(ctx, l); err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapLocation(l), nil
+}
+
+// DeleteLocation is the resolver for the deleteLocation field.
+func (r *mutationResolver) DeleteLocation(ctx context.Context, id string) (bool, error) {
+	lid, err := parseID("id", id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.Repos.DeleteLocation(ctx, lid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // ChangeUserRole is the resolver for the changeUserRole field.
 func (r *mutationResolver) ChangeUserRole(ctx context.Context, userID string, newRole string) (bool, error) {
-	if err := httpx.RequireAdmin(ctx); err != nil {
+	actorID, _, _ := httpx.UserFrom(ctx)
+	uid, err := parseID("userId", userID)
+	if err != nil {
 		return false, err
 	}
-	if err := r.Auth.ChangeUserRole(ctx, "Admin", parseID(userID), newRole); err != nil {
+	if err := r.Auth.ChangeUserRole(ctx, actorID, uid, newRole); err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
-	uid, role, asserted := httpx.UserFrom(ctx)
-	if role == "" {
-		return nil, fmt.Errorf("error role not found")
+// CreateRole is the resolver for the createRole field.
+func (r *mutationResolver) CreateRole(ctx context.Context, name string, permissions []string) (*model.Role, error) {
+	ro, err := r.Repos.CreateRole(ctx, name, permissions)
+	if err != nil {
+		return nil, err
 	}
-	if !asserted {
-		return nil, fmt.Errorf("error asserting value")
+	r.Authz.Invalidate()
+	return mapRole(ro), nil
+}
+
+// SetRolePermissions is the resolver for the setRolePermissions field.
+func (r *mutationResolver) SetRolePermissions(ctx context.Context, role string, permissions []string) (*model.Role, error) {
+	ro, err := r.Repos.SetRolePermissions(ctx, role, permissions)
+	if err != nil {
+		return nil, err
+	}
+	r.Authz.Invalidate()
+	return mapRole(ro), nil
+}
+
+// DeleteRole is the resolver for the deleteRole field.
+func (r *mutationResolver) DeleteRole(ctx context.Context, name string) (bool, error) {
+	if err := r.Repos.DeleteRole(ctx, name); err != nil {
+		return false, err
+	}
+	r.Authz.Invalidate()
+	return true, nil
+}
+
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.WebhookInput) (*model.CreateWebhookPayload, error) {
+	userID, _, _ := httpx.UserFrom(ctx)
+	w := &domain.Webhook{
+		URL: input.URL, Events: input.Events, Secret: ptrStr(input.Secret),
+		Active: input.Active == nil || *input.Active, CreatedBy: userID,
+	}
+	w, err := r.Repos.CreateWebhook(ctx, w)
+	if err != nil {
+		return nil, underArg("input", err)
+	}
+	return &model.CreateWebhookPayload{Webhook: mapWebhook(w), Secret: w.Secret}, nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.WebhookUpdateInput) (*model.Webhook, error) {
+	wid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.Repos.GetWebhookByID(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+	if input.URL != nil {
+		w.URL = *input.URL
+	}
+	if input.Events != nil {
+		w.Events = input.Events
 	}
+	if input.Secret != nil {
+		w.Secret = *input.Secret
+	}
+	if input.Active != nil {
+		w.Active = *input.Active
+	}
+	if w, err = r.Repos.UpdateWebhook(ctx, w); err != nil {
+		return nil, underArg("input", err)
+	}
+	return mapWebhook(w), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (bool, error) {
+	wid, err := parseID("id", id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.Repos.DeleteWebhook(ctx, wid); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RedeliverWebhook is the resolver for the redeliverWebhook field.
+func (r *mutationResolver) RedeliverWebhook(ctx context.Context, deliveryID string) (*model.WebhookDelivery, error) {
+	did, err := parseID("deliveryId", deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	d, err := r.Repos.RedeliverWebhook(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	return mapWebhookDelivery(d), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	uid, _, _ := httpx.UserFrom(ctx)
 
 	user, err := r.Repos.GetUserByUID(ctx, uid)
 	if err != nil {
-		if err.Error() == "pg: no rows in result set" {
-			return nil, fmt.Errorf("user with id %d not found", uid)
-		}
 		return nil, err
 	}
 	return mapUser(user), nil
@@ -186,52 +676,168 @@ func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 
 // Vehicle is the resolver for the vehicle field.
 func (r *queryResolver) Vehicle(ctx context.Context, id string) (*model.Vehicle, error) {
-	_, role, ok := httpx.UserFrom(ctx)
-	if !ok || role == "" {
-		return nil, httpx.ErrForbidden
+	vid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
 	}
-	v, err := r.Repos.GetVehicleByID(ctx, parseID(id))
+	v, err := r.Repos.GetVehicleByID(ctx, vid)
 	if err != nil {
-		if err.Error() == "pg: no rows in result set" {
-			return nil, fmt.Errorf("vehicle with id %s not found", id)
-		}
 		return nil, err
 	}
 	return mapVehicle(v), nil
 }
 
 // Vehicles is the resolver for the vehicles field.
-func (r *queryResolver) Vehicles(ctx context.Context, limit *int32, offset *int32) ([]*model.Vehicle, error) {
-	_, role, ok := httpx.UserFrom(ctx)
-	if !ok || role == "" {
-		return nil, httpx.ErrForbidden
+func (r *queryResolver) Vehicles(ctx context.Context, filter *model.VehicleFilter, sort *model.VehicleSort, direction *model.SortDirection, first *int32, after *string, includeDeleted *bool) (*model.VehicleConnection, error) {
+	f := mapVehicleFilter(filter)
+	if includeDeleted != nil && *includeDeleted {
+		if err := r.requirePermission(ctx, domain.PermVehicleReadDeleted); err != nil {
+			return nil, err
+		}
+		f.IncludeDeleted = true
+	}
+	page, err := pageArgs(ctx, first, after, 20)
+	if err != nil {
+		return nil, err
+	}
+	vehicles, err := r.Repos.ListVehicles(ctx, f, mapVehicleSort(sort, direction), page)
+	if err != nil {
+		return nil, err
+	}
+	return mapVehicleConnection(vehicles, page.After), nil
+}
+
+// DecodeVin is the resolver for the decodeVin field.
+func (r *queryResolver) DecodeVin(ctx context.Context, vin string) (*model.VinDecoding, error) {
+	info, err := domain.DecodeVIN(vin, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return mapVinDecoding(info), nil
+}
+
+// Locations is the resolver for the locations field.
+func (r *queryResolver) Locations(ctx context.Context, kinds []model.LocationKind) ([]*model.Location, error) {
+	ks := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		ks = append(ks, string(k))
+	}
+	ls, err := r.Repos.ListLocations(ctx, ks)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.Location, 0, len(ls))
+	for _, l := range ls {
+		out = append(out, mapLocation(l))
+	}
+	return out, nil
+}
+
+// Location is the resolver for the location field.
+func (r *queryResolver) Location(ctx context.Context, id string) (*model.Location, error) {
+	lid, err := parseID("id", id)
+	if err != nil {
+		return nil, err
 	}
-	vs := []*model.Vehicle{}
-	vehicles, err := r.Repos.ListVehicles(ctx, ptrInt32ToInt(limit, 100), ptrInt32ToInt(offset, 0))
+	l, err := r.Repos.GetLocationByID(ctx, lid)
 	if err != nil {
 		return nil, err
 	}
-	for i := range vehicles {
-		vs = append(vs, mapVehicle(vehicles[i]))
+	return mapLocation(l), nil
+}
+
+// VehiclesAt is the resolver for the vehiclesAt field.
+func (r *queryResolver) VehiclesAt(ctx context.Context, locationID string, first *int32, after *string) (*model.VehicleConnection, error) {
+	lid, err := parseID("locationId", locationID)
+	if err != nil {
+		return nil, err
 	}
-	return vs, nil
+	if _, err := r.Repos.GetLocationByID(ctx, lid); err != nil {
+		return nil, err
+	}
+	page, err := pageArgs(ctx, first, after, 20)
+	if err != nil {
+		return nil, err
+	}
+	vehicles, err := r.Repos.ListVehicles(ctx, domain.VehicleFilter{LocationID: &lid}, domain.VehicleSort{Field: domain.SortCreatedAt, Desc: true}, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapVehicleConnection(vehicles, page.After), nil
+}
+
+// Batches is the resolver for the batches field.
+func (r *queryResolver) Batches(ctx context.Context, query *string, first *int32, after *string) (*model.BatchConnection, error) {
+	page, err := pageArgs(ctx, first, after, 50)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := r.Repos.ListBatches(ctx, ptrStr(query), page)
+	if err != nil {
+		return nil, err
+	}
+	return mapBatchConnection(batches, page.After), nil
+}
+
+// Batch is the resolver for the batch field.
+func (r *queryResolver) Batch(ctx context.Context, number string) (*model.Batch, error) {
+	b, err := r.Repos.GetBatch(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return mapBatch(b), nil
+}
+
+// SearchVehicles is the resolver for the searchVehicles field.
+func (r *queryResolver) SearchVehicles(ctx context.Context, query string, first *int32) ([]*model.VehicleSearchResult, error) {
+	hits, err := r.Repos.SearchVehicles(ctx, query, ptrInt32ToInt(first, 20))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*model.VehicleSearchResult, 0, len(hits))
+	for _, h := range hits {
+		results = append(results, mapSearchHit(h))
+	}
+	return results, nil
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, limit *int32, offset *int32) ([]*model.User, error) {
-	if err := httpx.RequireAdmin(ctx); err != nil {
+func (r *queryResolver) Users(ctx context.Context, first *int32, after *string) (*model.UserConnection, error) {
+	page, err := pageArgs(ctx, first, after, 50)
+	if err != nil {
+		return nil, err
+	}
+	users, err := r.Repos.ListUsers(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapUserConnection(users, page.After), nil
+}
+
+// Roles is the resolver for the roles field.
+func (r *queryResolver) Roles(ctx context.Context) ([]*model.Role, error) {
+	roles, err := r.Repos.ListRoles(ctx)
+	if err != nil {
 		return nil, err
 	}
+	out := make([]*model.Role, 0, len(roles))
+	for _, ro := range roles {
+		out = append(out, mapRole(ro))
+	}
+	return out, nil
+}
 
-	users := []*model.User{}
-	items, err := r.Repos.ListUsers(ctx, ptrInt32ToInt(limit, 50), ptrInt32ToInt(offset, 0))
+// Permissions is the resolver for the permissions field.
+func (r *queryResolver) Permissions(ctx context.Context) ([]*model.Permission, error) {
+	perms, err := r.Repos.ListPermissions(ctx)
 	if err != nil {
 		return nil, err
 	}
-	for i := range items {
-		users = append(users, mapUser(items[i]))
+	out := make([]*model.Permission, 0, len(perms))
+	for _, p := range perms {
+		out = append(out, &model.Permission{Name: p.Name, Description: p.Description})
 	}
-	return users, nil
+	return out, nil
 }
 
 // MovementReport is the resolver for the movementReport field.
@@ -243,22 +849,314 @@ func (r *queryResolver) MovementReport(ctx context.Context, from time.Time, to t
 	return mapReport(reportResult), nil
 }
 
+// MovementTimeSeries is the resolver for the movementTimeSeries field.
+func (r *queryResolver) MovementTimeSeries(ctx context.Context, from time.Time, to time.Time, interval *model.ReportInterval, groupBy *model.MovementGroupBy, zeroFill *bool) ([]*model.MovementSeries, error) {
+	a := domain.SeriesArgs{From: from, To: to, Interval: domain.IntervalDay, ZeroFill: zeroFill != nil && *zeroFill}
+	if interval != nil {
+		a.Interval = string(*interval)
+	}
+	if groupBy != nil {
+		a.GroupBy = string(*groupBy)
+	}
+	series, err := r.Repos.MovementTimeSeries(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	return mapSeries(series), nil
+}
+
+// MovementMetadataSchemas is the resolver for the movementMetadataSchemas field.
+func (r *queryResolver) MovementMetadataSchemas(ctx context.Context) ([]*model.MovementMetadataSchema, error) {
+	ss, err := r.Repos.ListMovementSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.MovementMetadataSchema, 0, len(ss))
+	for _, s := range ss {
+		out = append(out, mapMovementSchema(s))
+	}
+	return out, nil
+}
+
+// MovementTypes is the resolver for the movementTypes field.
+func (r *queryResolver) MovementTypes(ctx context.Context) ([]*model.MovementType, error) {
+	ts, err := r.Repos.ListMovementTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.MovementType, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, mapMovementType(t))
+	}
+	return out, nil
+}
+
+// FleetStats is the resolver for the fleetStats field.
+func (r *queryResolver) FleetStats(ctx context.Context) (*model.FleetStats, error) {
+	stats, err := r.Stats.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapFleetStats(stats), nil
+}
+
+// AuditLog is the resolver for the auditLog field.
+func (r *queryResolver) AuditLog(ctx context.Context, filter *model.AuditFilter, first *int32, after *string) (*model.VehicleAuditConnection, error) {
+	page, err := pageArgs(ctx, first, after, 50)
+	if err != nil {
+		return nil, err
+	}
+	f, err := mapAuditFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := r.Repos.ListVehicleAudit(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapAuditConnection(entries, page.After), nil
+}
+
+// Events is the resolver for the events field.
+func (r *queryResolver) Events(ctx context.Context, after *string, first *int32, types []model.DomainEventType, aggregateType *string, aggregateID *string) (*model.DomainEventConnection, error) {
+	page, err := pageArgs(ctx, first, after, 100)
+	if err != nil {
+		return nil, err
+	}
+	f := domain.EventFilter{AggregateType: aggregateType}
+	for _, t := range types {
+		f.Types = append(f.Types, string(t))
+	}
+	if aggregateID != nil {
+		id, err := parseID("aggregateId", *aggregateID)
+		if err != nil {
+			return nil, err
+		}
+		f.AggregateID = &id
+	}
+	events, err := r.Repos.ListEvents(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapEventConnection(events, page.After), nil
+}
+
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	ws, err := r.Repos.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*model.Webhook, 0, len(ws))
+	for _, w := range ws {
+		out = append(out, mapWebhook(w))
+	}
+	return out, nil
+}
+
+// WebhookDeliveries is the resolver for the webhookDeliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, webhookID *string, status []model.WebhookDeliveryStatus, first *int32, after *string) (*model.WebhookDeliveryConnection, error) {
+	page, err := pageArgs(ctx, first, after, 50)
+	if err != nil {
+		return nil, err
+	}
+	var f domain.DeliveryFilter
+	if webhookID != nil {
+		id, err := parseID("webhookId", *webhookID)
+		if err != nil {
+			return nil, err
+		}
+		f.WebhookID = &id
+	}
+	for _, st := range status {
+		f.Statuses = append(f.Statuses, string(st))
+	}
+	ds, err := r.Repos.ListWebhookDeliveries(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapDeliveryConnection(ds, page.After), nil
+}
+
+// Permissions is the resolver for the permissions field.
+func (r *roleResolver) Permissions(ctx context.Context, obj *model.Role) ([]string, error) {
+	if obj.Permissions != nil {
+		return obj.Permissions, nil
+	}
+	id, err := parseID("id", obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loaders.For(ctx, r.Repos).PermissionsByRole.Load(ctx, id)
+}
+
+// MovementCreated is the resolver for the movementCreated field.
+func (r *subscriptionResolver) MovementCreated(ctx context.Context, vehicleID *string) (<-chan *model.Movement, error) {
+	var only int64
+	if vehicleID != nil {
+		id, err := parseID("vehicleId", *vehicleID)
+		if err != nil {
+			return nil, err
+		}
+		only = id
+	}
+	notices := r.Notifier.MovementsCreated(ctx)
+	out := make(chan *model.Movement)
+	go func() {
+		defer close(out)
+		for n := range notices {
+			if only != 0 && n.VehicleID != only {
+				continue
+			}
+			if !r.sessionLive(ctx) {
+				return
+			}
+			m, err := r.Repos.GetMovementByID(ctx, n.ID)
+			if err != nil {
+				log.Printf("movementCreated: load movement %d: %v", n.ID, err)
+				continue
+			}
+			select {
+			case out <- mapMovement(m):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// VehicleChanged is the resolver for the vehicleChanged field.
+func (r *subscriptionResolver) VehicleChanged(ctx context.Context, id *string) (<-chan *model.VehicleChange, error) {
+	var only int64
+	if id != nil {
+		vid, err := parseID("id", *id)
+		if err != nil {
+			return nil, err
+		}
+		only = vid
+	}
+	_, role, _ := httpx.UserFrom(ctx)
+	notices := r.Notifier.VehiclesChanged(ctx)
+	out := make(chan *model.VehicleChange)
+	go func() {
+		defer close(out)
+		for n := range notices {
+			if only != 0 && n.ID != only {
+				continue
+			}
+			if !r.sessionLive(ctx) {
+				return
+			}
+			change := &model.VehicleChange{Action: model.AuditAction(n.Action), VehicleID: idStr(n.ID)}
+			if n.Action != domain.AuditPurge {
+				vs, err := r.Repos.GetVehiclesByIDs(ctx, []int64{n.ID})
+				if err != nil {
+					log.Printf("vehicleChanged: load vehicle %d: %v", n.ID, err)
+					continue
+				}
+				v, ok := vs[n.ID]
+				if ok && v.DeletedAt != nil {
+					ok, err = r.Authz.Can(ctx, role, domain.PermVehicleReadDeleted)
+					if err != nil {
+						log.Printf("vehicleChanged: check %s: %v", domain.PermVehicleReadDeleted, err)
+					}
+				}
+				if ok {
+					change.Vehicle = mapVehicle(v)
+				}
+			}
+			select {
+			case out <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Role is the resolver for the role field.
+func (r *userResolver) Role(ctx context.Context, obj *model.User) (*model.Role, error) {
+	if obj.Role != nil {
+		return obj.Role, nil
+	}
+	ro, err := loaders.For(ctx, r.Repos).RoleByID.Load(ctx, obj.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	return mapRole(ro), nil
+}
+
+// CurrentLocation is the resolver for the currentLocation field.
+func (r *vehicleResolver) CurrentLocation(ctx context.Context, obj *model.Vehicle) (*model.Location, error) {
+	return r.loadLocation(ctx, "currentLocationId", obj.CurrentLocationID)
+}
+
 // Movements is the resolver for the movements field.
-func (r *vehicleResolver) Movements(ctx context.Context, obj *model.Vehicle, limit *int32, offset *int32) ([]*model.Movement, error) {
-	if _, role, ok := httpx.UserFrom(ctx); !ok || role == "" {
-		return nil, httpx.ErrForbidden
+func (r *vehicleResolver) Movements(ctx context.Context, obj *model.Vehicle, first *int32, after *string) (*model.MovementConnection, error) {
+	page, err := pageArgs(ctx, first, after, 20)
+	if err != nil {
+		return nil, err
+	}
+	vehicleID, err := parseID("id", obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	var records *domain.Page[*domain.Movement]
+	if page.After == nil {
+		records, err = loaders.For(ctx, r.Repos).MovementsByVehicle.Load(ctx, loaders.MovementPageKey{VehicleID: vehicleID, First: page.First})
+	} else {
+		records, err = r.Repos.ListMovementsByVehicle(ctx, vehicleID, page)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return mapMovementConnection(records, page.After), nil
+}
 
-	records, err := r.Repos.ListMovementsByVehicle(ctx, parseID(obj.ID), ptrInt32ToInt(limit, 20), ptrInt32ToInt(offset, 0))
+// History is the resolver for the history field.
+func (r *vehicleResolver) History(ctx context.Context, obj *model.Vehicle, first *int32, after *string) (*model.VehicleAuditConnection, error) {
+	page, err := pageArgs(ctx, first, after, 20)
 	if err != nil {
 		return nil, err
 	}
+	vehicleID, err := parseID("id", obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := r.Repos.ListVehicleAudit(ctx, domain.AuditFilter{VehicleID: &vehicleID}, page)
+	if err != nil {
+		return nil, err
+	}
+	return mapAuditConnection(entries, page.After), nil
+}
 
-	result := make([]*model.Movement, 0, len(records))
-	for _, movement := range records {
-		result = append(result, mapMovement(movement))
+// Actor is the resolver for the actor field.
+func (r *vehicleAuditEntryResolver) Actor(ctx context.Context, obj *model.VehicleAuditEntry) (*model.User, error) {
+	if obj.ActorID == nil {
+		return nil, nil
 	}
-	return result, nil
+	id, err := parseID("actorId", *obj.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := loaders.For(ctx, r.Repos).UserByID.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapUser(u), nil
+}
+
+// Batch returns BatchResolver implementation.
+func (r *Resolver) Batch() BatchResolver { return &batchResolver{r} }
+
+// Movement returns MovementResolver implementation.
+func (r *Resolver) Movement() MovementResolver { return &movementResolver{r} }
+
+// MovementCorrection returns MovementCorrectionResolver implementation.
+func (r *Resolver) MovementCorrection() MovementCorrectionResolver {
+	return &movementCorrectionResolver{r}
 }
 
 // Mutation returns MutationResolver implementation.
@@ -267,9 +1165,30 @@ func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Role returns RoleResolver implementation.
+func (r *Resolver) Role() RoleResolver { return &roleResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
 // Vehicle returns VehicleResolver implementation.
 func (r *Resolver) Vehicle() VehicleResolver { return &vehicleResolver{r} }
 
+// VehicleAuditEntry returns VehicleAuditEntryResolver implementation.
+func (r *Resolver) VehicleAuditEntry() VehicleAuditEntryResolver {
+	return &vehicleAuditEntryResolver{r}
+}
+
+type batchResolver struct{ *Resolver }
+type movementResolver struct{ *Resolver }
+type movementCorrectionResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type roleResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
 type vehicleResolver struct{ *Resolver }
+type vehicleAuditEntryResolver struct{ *Resolver }
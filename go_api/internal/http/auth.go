@@ -2,18 +2,20 @@ package httpx
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/Kenfoxfire/Gear-Core-app/internal/apperr"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type ctxKey string
 
 const (
-	UserIDKey ctxKey = "uid"
-	RoleKey   ctxKey = "role"
+	UserIDKey    ctxKey = "uid"
+	RoleKey      ctxKey = "role"
+	SessionIDKey ctxKey = "sid"
 )
 
 func WithUser(ctx context.Context, uid int64, role string) context.Context {
@@ -26,36 +28,77 @@ func UserFrom(ctx context.Context) (int64, string, bool) {
 	return uid, role, ok1 && ok2
 }
 
-func AuthMiddleware(secret []byte) func(http.Handler) http.Handler {
+func WithSession(ctx context.Context, sid int64) context.Context {
+	return context.WithValue(ctx, SessionIDKey, sid)
+}
+func SessionFrom(ctx context.Context) (int64, bool) {
+	sid, ok := ctx.Value(SessionIDKey).(int64)
+	return sid, ok
+}
+
+// SessionLookup returns the user's current role while the session is live, and an error once
+// it has been revoked or has expired.
+type SessionLookup func(ctx context.Context, sessionID, userID int64) (string, error)
+
+// AuthMiddleware authenticates Bearer tokens. The role comes from lookup rather than the token,
+// so role changes and logouts take effect on the next request.
+func AuthMiddleware(secret []byte, lookup SessionLookup) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			h := r.Header.Get("Authorization")
-			if h == "" || !strings.HasPrefix(h, "Bearer ") {
-				next.ServeHTTP(w, r)
-				return
-			}
-			tokStr := strings.TrimPrefix(h, "Bearer ")
-			tok, err := jwt.Parse(tokStr, func(t *jwt.Token) (any, error) { return secret, nil })
-			if err == nil && tok.Valid {
-				if c, ok := tok.Claims.(jwt.MapClaims); ok {
-					uidF, hasUID := c["uid"].(float64)
-					role, _ := c["role"].(string)
-					if hasUID && role != "" {
-						r = r.WithContext(WithUser(r.Context(), int64(uidF), role))
-					}
-				}
+			if ctx, ok := authenticate(r.Context(), secret, lookup, r.Header.Get("Authorization")); ok {
+				r = r.WithContext(ctx)
 			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-var ErrForbidden = errors.New("forbidden")
+// WebsocketInit authenticates GraphQL websocket connections, which cannot send headers, from the
+// Authorization entry of the connection_init payload. A connection without one stays anonymous;
+// one with an invalid token is refused.
+func WebsocketInit(secret []byte, lookup SessionLookup) transport.WebsocketInitFunc {
+	return func(ctx context.Context, p transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		h := p.Authorization()
+		if h == "" {
+			return ctx, nil, nil
+		}
+		ctx, ok := authenticate(ctx, secret, lookup, h)
+		if !ok {
+			return nil, nil, ErrUnauthenticated
+		}
+		return ctx, nil, nil
+	}
+}
 
-func RequireAdmin(ctx context.Context) error {
-	_, role, ok := UserFrom(ctx)
-	if !ok || role != "Admin" {
-		return ErrForbidden
+// authenticate adds the user and session of a valid "Bearer <token>" header to ctx.
+func authenticate(ctx context.Context, secret []byte, lookup SessionLookup, header string) (context.Context, bool) {
+	tokStr, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return ctx, false
 	}
-	return nil
+	tok, err := jwt.Parse(tokStr, func(t *jwt.Token) (any, error) { return secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !tok.Valid {
+		return ctx, false
+	}
+	c, ok := tok.Claims.(jwt.MapClaims)
+	if !ok {
+		return ctx, false
+	}
+	uidF, hasUID := c["uid"].(float64)
+	sidF, hasSID := c["sid"].(float64)
+	if !hasUID || !hasSID {
+		return ctx, false
+	}
+	uid, sid := int64(uidF), int64(sidF)
+	role, err := lookup(ctx, sid, uid)
+	if err != nil || role == "" {
+		return ctx, false
+	}
+	return WithSession(WithUser(ctx, uid, role), sid), true
 }
+
+var (
+	ErrUnauthenticated = apperr.ErrUnauthenticated
+	ErrForbidden       = apperr.ErrForbidden
+)
@@ -15,3 +15,12 @@ func CORS(allowOrigins string) func(http.Handler) http.Handler {
 	})
 	return c.Handler
 }
+
+// CheckOrigin accepts websocket upgrades from the same origins CORS allows, plus requests
+// without an Origin header (non-browser clients).
+func CheckOrigin(allowOrigins string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowOrigins == "*" || origin == allowOrigins
+	}
+}
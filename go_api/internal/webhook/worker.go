@@ -0,0 +1,144 @@
+// Package webhook delivers domain events to the webhooks subscribed to them.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-GearCore-Event"
+	HeaderDelivery  = "X-GearCore-Delivery"
+	HeaderTimestamp = "X-GearCore-Timestamp"
+	HeaderSignature = "X-GearCore-Signature"
+)
+
+// Sign returns the HeaderSignature value for body sent at timestamp (Unix seconds): the hex
+// HMAC-SHA256 of "<timestamp>.<body>" under secret, prefixed with "sha256=". Receivers should
+// recompute it and reject stale timestamps.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Worker fans domain events out to deliveries and sends the ones that are due. Any number of
+// workers may run against the same database.
+type Worker struct {
+	Repos       *domain.Repos
+	Client      *http.Client
+	Interval    time.Duration // pause between polls when there is nothing to do
+	MaxAttempts int           // attempts before a delivery is marked FAILED
+	BaseBackoff time.Duration // wait after the first failure; doubled after each further one
+	MaxBackoff  time.Duration
+}
+
+// batchSize bounds the events and deliveries handled per poll.
+const batchSize = 50
+
+// Run polls until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	t := time.NewTicker(w.Interval)
+	defer t.Stop()
+	for {
+		if w.poll(ctx) && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// poll does one round of work and reports whether there may be more waiting.
+func (w *Worker) poll(ctx context.Context) bool {
+	events, err := w.Repos.DispatchEvents(ctx, batchSize)
+	if err != nil {
+		log.Printf("webhook: dispatch events: %v", err)
+		return false
+	}
+	// A lease longer than one full send timeout per claimed delivery keeps them from being sent twice.
+	lease := time.Duration(batchSize)*w.Client.Timeout + time.Minute
+	ds, err := w.Repos.ClaimDeliveries(ctx, batchSize, lease)
+	if err != nil {
+		log.Printf("webhook: claim deliveries: %v", err)
+		return false
+	}
+	for _, d := range ds {
+		w.attempt(ctx, d)
+	}
+	return events == batchSize || len(ds) == batchSize
+}
+
+// attempt sends d once and records the outcome.
+func (w *Worker) attempt(ctx context.Context, d *domain.WebhookDelivery) {
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus, d.LastError = 0, ""
+	if d.Webhook == nil || !d.Webhook.Active {
+		d.Status, d.LastError = domain.DeliveryFailed, "webhook is disabled"
+	} else if code, err := w.send(ctx, d); err != nil {
+		d.ResponseStatus, d.LastError = code, err.Error()
+		if d.Attempts >= w.MaxAttempts {
+			d.Status = domain.DeliveryFailed
+		} else {
+			d.NextAttemptAt = now.Add(w.backoff(d.Attempts))
+		}
+	} else {
+		d.Status, d.ResponseStatus = domain.DeliverySucceeded, code
+	}
+	if err := w.Repos.SaveDeliveryAttempt(ctx, d); err != nil {
+		log.Printf("webhook: save delivery %d: %v", d.ID, err)
+	}
+}
+
+// backoff is the wait after the given number of failed attempts.
+func (w *Worker) backoff(attempts int) time.Duration {
+	b := w.BaseBackoff
+	for i := 1; i < attempts && b < w.MaxBackoff; i++ {
+		b *= 2
+	}
+	return min(b, w.MaxBackoff)
+}
+
+// send posts d and returns the response status; any status outside 2xx is an error.
+func (w *Worker) send(ctx context.Context, d *domain.WebhookDelivery) (int, error) {
+	body := []byte(d.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GearCore-Webhooks/1")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(d.Webhook.Secret, ts, body))
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Kenfoxfire/Gear-Core-app/internal/domain"
+)
+
+func TestSign(t *testing.T) {
+	const body = `{"event":"vehicle.created"}`
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{"event", "s3cret", 1700000000, body, "sha256=9aeea28fe3298c67decf2bc15297b4fb846ed54af305213f3b22172578c7aafa"},
+		{"timestamp is signed", "s3cret", 1700000001, body, "sha256=7509dd9e0323505d59a07dd1529cdc3c68c60de01b7c5c23786e862e68e8898c"},
+		{"empty", "", 0, "", "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	const body = `{"event":"vehicle.created","vehicleId":7}`
+	tests := []struct {
+		name     string
+		status   int
+		wantCode int
+		wantErr  bool
+	}{
+		{"success", http.StatusOK, 200, false},
+		{"no content", http.StatusNoContent, 204, false},
+		{"not modified", http.StatusNotModified, 304, true},
+		{"server error", http.StatusInternalServerError, 500, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				gotBody, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			w := &Worker{Client: &http.Client{Timeout: 5 * time.Second}}
+			d := &domain.WebhookDelivery{
+				ID: 42, EventType: "vehicle.created", Body: body,
+				Webhook: &domain.Webhook{URL: srv.URL, Secret: "s3cret", Active: true},
+			}
+			code, err := w.send(context.Background(), d)
+			if code != tt.wantCode || (err != nil) != tt.wantErr {
+				t.Fatalf("send = %d, %v; want %d, error %v", code, err, tt.wantCode, tt.wantErr)
+			}
+			if string(gotBody) != body {
+				t.Errorf("body = %s, want %s", gotBody, body)
+			}
+			if h := got.Header.Get(HeaderEvent); h != "vehicle.created" {
+				t.Errorf("%s = %q", HeaderEvent, h)
+			}
+			if h := got.Header.Get(HeaderDelivery); h != "42" {
+				t.Errorf("%s = %q", HeaderDelivery, h)
+			}
+			ts, err := strconv.ParseInt(got.Header.Get(HeaderTimestamp), 10, 64)
+			if err != nil {
+				t.Fatalf("%s: %v", HeaderTimestamp, err)
+			}
+			if h, want := got.Header.Get(HeaderSignature), Sign("s3cret", ts, []byte(body)); h != want {
+				t.Errorf("%s = %q, want %q", HeaderSignature, h, want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	w := &Worker{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{30, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := w.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}